@@ -0,0 +1,174 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+)
+
+//Alias exposes the service identified by target under another id. The alias shares the cache entry and the lifetime
+//of the target service, the target can be declared later or in a scope of the container
+func (rc *RuntimeContainer) Alias(alias, target string) error {
+	if alias == "" || alias == target {
+		return fmt.Errorf("Wrong alias '%s' of the service '%s'", alias, target)
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	return rc.addAlias(alias, target)
+}
+
+//addAlias must be called under the mutex
+func (rc *RuntimeContainer) addAlias(alias, target string) error {
+	err := assertValidID(alias)
+	if err != nil {
+		return err
+	}
+
+	err = rc.assertNotFrozen(alias)
+	if err != nil {
+		return err
+	}
+
+	err = rc.assertNoDuplicates(alias)
+	if err != nil {
+		return err
+	}
+
+	rc.aliases[alias] = target
+
+	return nil
+}
+
+//Bind exposes the service identified by id as the implementation of the interface I. The bound service is used for
+//autowired arguments of type I and can be fetched with ResolveBound or by the name of the interface, e.g. "mocks.Cache"
+func Bind[I any](rc *RuntimeContainer, id string) error {
+	return rc.bind(reflect.TypeOf((*I)(nil)).Elem(), id)
+}
+
+//ResolveBound fetches a cached service bound to the interface I
+func ResolveBound[I any](c Container) (I, error) {
+	return Resolve[I](c, getInterfaceID(reflect.TypeOf((*I)(nil)).Elem()))
+}
+
+func (rc *RuntimeContainer) bind(interfaceType reflect.Type, id string) error {
+	if interfaceType.Kind() != reflect.Interface {
+		return fmt.Errorf("Only interfaces can be bound to services, '%s' is given [check '%s' service]", interfaceType, id)
+	}
+
+	if serviceType, isTyped := rc.findServiceType(id); isTyped && !serviceType.AssignableTo(interfaceType) {
+		return &TypeMismatchError{ServiceID: id, Expected: interfaceType, Provided: serviceType}
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	interfaceID := getInterfaceID(interfaceType)
+	err := rc.addAlias(interfaceID, id)
+	if err != nil {
+		return err
+	}
+	rc.bindings[interfaceType] = interfaceID
+
+	return nil
+}
+
+//getInterfaceID gives the id of the alias which exposes the service bound to the interface
+func getInterfaceID(interfaceType reflect.Type) string {
+	return interfaceType.String()
+}
+
+//findAlias gives the target of the alias declared in the scopes chain, a service declared in a scope overrides
+//an alias with the same id of its parents
+func (rc *RuntimeContainer) findAlias(id string) (string, bool) {
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		_, isConstructor := cont.constructors[id]
+		_, isNewFunc := cont.newFuncConstructors[id]
+		target, isAlias := cont.aliases[id]
+		cont.mutex.RUnlock()
+
+		if isConstructor || isNewFunc {
+			return "", false
+		}
+
+		if isAlias {
+			return target, true
+		}
+	}
+
+	return "", false
+}
+
+//findBinding gives the id of the alias of the service bound to the interface in the scopes chain
+func (rc *RuntimeContainer) findBinding(interfaceType reflect.Type) (string, bool) {
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		interfaceID, isBound := cont.bindings[interfaceType]
+		cont.mutex.RUnlock()
+
+		if isBound {
+			return interfaceID, true
+		}
+	}
+
+	return "", false
+}
+
+//findServiceType gives the declared type of the service from the scopes chain
+func (rc *RuntimeContainer) findServiceType(id string) (reflect.Type, bool) {
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		serviceType, isTyped := cont.serviceTypes[id]
+		cont.mutex.RUnlock()
+
+		if isTyped {
+			return serviceType, true
+		}
+	}
+
+	return nil, false
+}
+
+//getAliased fetches the target of the alias, the alias is visited by the cycle detector as a usual service
+func (r *resolution) getAliased(alias, target string, isCached bool) (interface{}, error) {
+	r.cycleDetector.VisitBeforeRecursion(alias)
+
+	if r.cycleDetector.IsEnabled() && r.cycleDetector.HasCycle() {
+		return nil, &CycleError{Path: r.cycleDetector.GetCycle()}
+	}
+
+	service, err := r.next(r.RuntimeContainer, alias).GetSecure(target, isCached)
+	if err != nil {
+		return nil, err
+	}
+	r.cycleDetector.VisitAfterRecursion(alias)
+
+	return service, nil
+}
+
+//getAliases exposes a copy of aliases for merge
+func (rc *RuntimeContainer) getAliases() map[string]string {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	aliases := make(map[string]string, len(rc.aliases))
+	for alias, target := range rc.aliases {
+		aliases[alias] = target
+	}
+
+	return aliases
+}
+
+//getBindings exposes a copy of interface bindings for merge
+func (rc *RuntimeContainer) getBindings() map[reflect.Type]string {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	bindings := make(map[reflect.Type]string, len(rc.bindings))
+	for interfaceType, interfaceID := range rc.bindings {
+		bindings[interfaceType] = interfaceID
+	}
+
+	return bindings
+}
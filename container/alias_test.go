@@ -0,0 +1,225 @@
+package container
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func createContainerWithCaches() *RuntimeContainer {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("in_memory_cache", mocks.NewInMemoryCache)
+	cont.AddNewMethod("other_cache", mocks.NewInMemoryCache)
+	cont.AddNewMethod("incompatible_cache", mocks.NewIncompatibleCache)
+
+	return cont
+}
+
+func TestAliasSharesCacheWithTarget(t *testing.T) {
+	cont := createContainerWithCaches()
+	assertNoError(cont.Alias("cache", "in_memory_cache"), t)
+	assertNoError(cont.Alias("books_cache", "cache"), t)
+
+	target := cont.Get("in_memory_cache", true)
+	if cont.Get("cache", true) != target || cont.Get("books_cache", true) != target {
+		t.Error("Aliases should give the cached instance of the target service")
+	}
+
+	if cont.Get("cache", false) == target {
+		t.Error("A non cached alias should give a new instance of the target service")
+	}
+
+	if !cont.Exists("books_cache") {
+		t.Error("The alias should exist in the container")
+	}
+}
+
+func TestAliasInScope(t *testing.T) {
+	cont := createContainerWithCaches()
+	cont.Alias("cache", "in_memory_cache")
+	cont.SetLifetime("in_memory_cache", Scoped)
+
+	scope := cont.NewScope()
+	scope.AddNewMethod("in_memory_cache", func() mocks.Cache { return countingCache{} })
+
+	_, isScoped := scope.Get("cache", true).(countingCache)
+	if !isScoped {
+		t.Error("The alias should give the target service declared in the scope")
+	}
+}
+
+func TestAliasErrors(t *testing.T) {
+	cont := createContainerWithCaches()
+
+	err := cont.Alias("cache", "cache")
+	assertErrorText("Wrong alias 'cache' of the service 'cache'", err, t)
+
+	err = cont.Alias("other_cache", "in_memory_cache")
+	assertErrorText("Detected duplicated dependency declaration 'other_cache'", err, t)
+
+	assertNoError(cont.Alias("cache", "in_memory_cache"), t)
+	err = cont.AddNewMethod("cache", mocks.NewInMemoryCache)
+	assertErrorText("Detected duplicated dependency declaration 'cache'", err, t)
+
+	cont.Alias("missing_cache", "unknown_cache")
+	_, err = cont.GetSecure("missing_cache", true)
+	assertErrorText("Unknown dependency 'unknown_cache'", err, t)
+
+	cont.AddNewMethod("cache_manager", mocks.NewCacheManager, "failing_cache")
+	cont.AddConstructor("failed_cache", func(c Container) (interface{}, error) {
+		return nil, errors.New("Cache is not available")
+	})
+	cont.Alias("failing_cache", "failed_cache")
+	_, err = cont.GetSecure("cache_manager", true)
+	constructorErr := &ConstructorError{}
+	if !errors.As(err, &constructorErr) {
+		t.Fatalf("A constructor error is expected, but %v is given", err)
+	}
+	if !strings.HasPrefix(constructorErr.Trace(), "cache_manager -> failing_cache -> failed_cache: Cache is not available") {
+		t.Errorf("The resolution path should contain the alias, but %s is given", constructorErr.Trace())
+	}
+
+	assertNoError(cont.Freeze(), t)
+	err = cont.Alias("frozen_cache", "in_memory_cache")
+	assertErrorText("Cannot declare the service 'frozen_cache' as the container is frozen", err, t)
+}
+
+func TestAliasCycles(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.Alias("a", "b")
+	cont.Alias("b", "a")
+
+	_, err := cont.GetSecure("a", true)
+	assertErrorText("Detected dependencies' cycle: a->b->a", err, t)
+
+	cont.AddNewMethod("cache_manager", mocks.NewCacheManager, "cache")
+	cont.Alias("cache", "cache_manager")
+
+	_, err = cont.GetSecure("cache_manager", true)
+	assertErrorText("Detected dependencies' cycle: cache_manager->cache->cache_manager [check 'cache_manager' service]", err, t)
+}
+
+func TestBind(t *testing.T) {
+	cont := createContainerWithCaches()
+	assertNoError(cont.AddAutowiredNewMethod("cache_manager", mocks.NewCacheManager), t)
+
+	_, err := cont.GetSecure("cache_manager", true)
+	assertErrorText(
+		"Several services of type 'mocks.Cache' are declared for the argument 1 of the Constr function: "+
+			"in_memory_cache, other_cache [check 'cache_manager' service]",
+		err,
+		t,
+	)
+
+	assertNoError(Bind[mocks.Cache](cont, "other_cache"), t)
+	AssertExpectedDependency(cont, "cache_manager", mocks.NewCacheManager(cont.Get("other_cache", true).(mocks.Cache)), t)
+
+	cache, err := ResolveBound[mocks.Cache](cont)
+	assertNoError(err, t)
+	if cache != cont.Get("other_cache", true) {
+		t.Error("The bound service should share the cache with the implementation")
+	}
+
+	if !cont.Exists("mocks.Cache") {
+		t.Error("The bound interface should exist in the container")
+	}
+
+	scope := cont.NewScope()
+	assertNoError(Bind[mocks.Cache](scope, "in_memory_cache"), t)
+	cache, err = ResolveBound[mocks.Cache](scope)
+	assertNoError(err, t)
+	if cache != cont.Get("in_memory_cache", true) {
+		t.Error("The interface should be bound to another implementation in the scope")
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	cont := createContainerWithCaches()
+
+	err := Bind[mocks.Cache](cont, "incompatible_cache")
+	assertErrorText(
+		"Cannot use the service 'incompatible_cache' of type 'mocks.IncompatibleCache' as 'mocks.Cache' "+
+			"[check 'incompatible_cache' service]",
+		err,
+		t,
+	)
+
+	err = Bind[*mocks.InMemoryCache](cont, "in_memory_cache")
+	assertErrorText(
+		"Only interfaces can be bound to services, '*mocks.InMemoryCache' is given [check 'in_memory_cache' service]",
+		err,
+		t,
+	)
+
+	assertNoError(Bind[mocks.Cache](cont, "in_memory_cache"), t)
+	err = Bind[mocks.Cache](cont, "other_cache")
+	assertErrorText("Detected duplicated dependency declaration 'mocks.Cache'", err, t)
+}
+
+func TestAliasesFromConfig(t *testing.T) {
+	tree := Tree{
+		Node{ID: "in_memory_cache", NewFunc: mocks.NewInMemoryCache},
+		Node{ID: "other_cache", NewFunc: mocks.NewInMemoryCache},
+		Node{ID: "cache_manager", NewFunc: mocks.NewCacheManager, Autowire: true},
+		Node{ID: "books_cache", AliasOf: "mocks.Cache"},
+		Node{Interface: (*mocks.Cache)(nil), AliasOf: "other_cache"},
+	}
+
+	assertNoError(ValidateGraphSecure(tree), t)
+
+	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfigSecure(tree)
+	assertNoError(err, t)
+	otherCache := cont.Get("other_cache", true)
+	AssertExpectedDependency(cont, "cache_manager", mocks.NewCacheManager(otherCache.(mocks.Cache)), t)
+	AssertExpectedDependency(cont, "books_cache", otherCache, t)
+
+	graph, err := ExportGraph(tree, DotFormat)
+	assertNoError(err, t)
+	for _, expectedLine := range []string{
+		`"books_cache" [shape=box, style=rounded];`,
+		`"mocks.Cache" [shape=box, style=rounded];`,
+		`"cache_manager" -> "mocks.Cache";`,
+		`"books_cache" -> "mocks.Cache";`,
+		`"mocks.Cache" -> "other_cache";`,
+	} {
+		if !strings.Contains(graph, expectedLine) {
+			t.Errorf("The exported graph should contain %s:\n%s", expectedLine, graph)
+		}
+	}
+}
+
+func TestAliasesValidation(t *testing.T) {
+	err := ValidateConfigSecure(Tree{
+		Node{AliasOf: "in_memory_cache"},
+		Node{Interface: (*mocks.Cache)(nil)},
+		Node{Interface: mocks.NewInMemoryCache(), AliasOf: "in_memory_cache"},
+		Node{ID: "cache", AliasOf: "in_memory_cache", NewFunc: mocks.NewInMemoryCache},
+	})
+	assertErrorText(
+		"An alias should be defined with a non empty id or interface, see 'Node: {ID: ; AliasOf: in_memory_cache}';\n"+
+			"An interface should be bound to a service defined in AliasOf, see 'Node: {Interface: *mocks.Cache; AliasOf: }';\n"+
+			"The interface should be defined as a nil pointer to an interface, e.g. (*Logger)(nil), "+
+			"see 'Node: {Interface: *mocks.InMemoryCache; AliasOf: in_memory_cache}';\n"+
+			"The alias node should contain only an id or an interface and AliasOf, see 'Node: {ID: cache; AliasOf: in_memory_cache}'",
+		err,
+		t,
+	)
+
+	err = ValidateGraphSecure(Tree{
+		Node{ID: "incompatible_cache", NewFunc: mocks.NewIncompatibleCache},
+		Node{Interface: (*mocks.Cache)(nil), AliasOf: "incompatible_cache"},
+		Node{ID: "cache", AliasOf: "unknown_cache"},
+		Node{ID: "a", AliasOf: "b"},
+		Node{ID: "b", AliasOf: "a"},
+	})
+	assertErrorText(
+		"Cannot use the provided dependency 'incompatible_cache' of type 'mocks.IncompatibleCache' as 'mocks.Cache' "+
+			"in the Constr function call [check 'mocks.Cache' service];\n"+
+			"Unknown dependency 'unknown_cache' [check 'cache' service];\n"+
+			"Detected dependencies' cycle: a->b->a",
+		err,
+		t,
+	)
+}
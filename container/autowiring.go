@@ -0,0 +1,135 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//AddAutowiredNewMethod registers a New Service method which arguments are resolved by their types rather than by ids,
+//fails if id already exists
+func (rc *RuntimeContainer) AddAutowiredNewMethod(id string, typedConstructor interface{}) error {
+	constrFunc, err := rc.convertAutowiredNewMethodToNewFuncConstructor(typedConstructor, id)
+	if err != nil {
+		return err
+	}
+
+	return rc.setNewFuncConstructor(id, constrFunc, newAutowiredNewMethodDeclaration(typedConstructor), true)
+}
+
+//SetAutowiredNewMethod overrides an existing service declaration with an autowired New method or adds a new one
+func (rc *RuntimeContainer) SetAutowiredNewMethod(id string, typedConstructor interface{}) error {
+	constrFunc, err := rc.convertAutowiredNewMethodToNewFuncConstructor(typedConstructor, id)
+	if err != nil {
+		return err
+	}
+
+	return rc.setNewFuncConstructor(id, constrFunc, newAutowiredNewMethodDeclaration(typedConstructor), false)
+}
+
+//convertAutowiredNewMethodToNewFuncConstructor creates a Callback that will call a New method of a Service with
+//the services found by the types of its arguments. The services are looked up at call time, so they can be declared
+//in any order. Variadic arguments are left empty.
+func (rc *RuntimeContainer) convertAutowiredNewMethodToNewFuncConstructor(
+	newMethod interface{},
+	serviceId string,
+) (NewFuncConstructor, error) {
+	reflectedNewMethod := reflect.ValueOf(newMethod)
+
+	argumentsCount := 0
+	if isFunction(reflectedNewMethod) {
+		argumentsCount = reflectedNewMethod.Type().NumIn() - contextArgumentsCount(reflectedNewMethod.Type())
+	}
+
+	err := assertNewMethodDeclaration(reflectedNewMethod, argumentsCount, serviceId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateConstructorReturnValues(reflectedNewMethod, serviceId)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c Container, isCached bool) (interface{}, error) {
+		//services are looked up in the scope where the autowired service is created
+		currentContainer := rc
+		if r, ok := c.(*resolution); ok {
+			currentContainer = r.RuntimeContainer
+		}
+
+		newMethodArgumentNames, err := currentContainer.autowireArguments(reflectedNewMethod, serviceId)
+		if err != nil {
+			return nil, err
+		}
+
+		return callNewMethod(reflectedNewMethod, newMethodArgumentNames, c, serviceId, isCached)
+	}, nil
+}
+
+//autowireArguments finds a single declared service for every argument of a New method by the argument type
+func (rc *RuntimeContainer) autowireArguments(reflectedNewMethod reflect.Value, serviceId string) ([]string, error) {
+	newMethodType := reflectedNewMethod.Type()
+	argumentsCount := newMethodType.NumIn()
+	if newMethodType.IsVariadic() {
+		argumentsCount--
+	}
+
+	newMethodArgumentNames := make([]string, 0, argumentsCount)
+	errs := []error{}
+	for i := contextArgumentsCount(newMethodType); i < argumentsCount; i++ {
+		argumentType := newMethodType.In(i)
+		if interfaceID, isBound := rc.findBinding(argumentType); isBound {
+			newMethodArgumentNames = append(newMethodArgumentNames, interfaceID)
+			continue
+		}
+
+		candidates := rc.findServicesByType(argumentType, serviceId)
+
+		switch len(candidates) {
+		case 0:
+			errs = append(errs, fmt.Errorf(
+				"No service of type '%s' is declared for the argument %d of the Constr function [check '%s' service]",
+				argumentType,
+				i+1,
+				serviceId,
+			))
+		case 1:
+			newMethodArgumentNames = append(newMethodArgumentNames, candidates[0])
+		default:
+			errs = append(errs, fmt.Errorf(
+				"Several services of type '%s' are declared for the argument %d of the Constr function: %s [check '%s' service]",
+				argumentType,
+				i+1,
+				strings.Join(candidates, ", "),
+				serviceId,
+			))
+		}
+	}
+
+	return newMethodArgumentNames, mergeErrors(errs)
+}
+
+//findServicesByType gives sorted ids of services in the scopes chain which declared type can be used as the expected type
+func (rc *RuntimeContainer) findServicesByType(expectedType reflect.Type, excludedServiceID string) []string {
+	serviceIDs := []string{}
+	knownServiceIDs := map[string]bool{excludedServiceID: true}
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		for serviceID, serviceType := range cont.serviceTypes {
+			if knownServiceIDs[serviceID] {
+				continue
+			}
+			knownServiceIDs[serviceID] = true
+
+			if serviceType.AssignableTo(expectedType) {
+				serviceIDs = append(serviceIDs, serviceID)
+			}
+		}
+		cont.mutex.RUnlock()
+	}
+	sort.Strings(serviceIDs)
+
+	return serviceIDs
+}
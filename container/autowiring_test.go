@@ -0,0 +1,119 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func TestAutowiredNewMethod(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func() *mocks.FakeDb {
+		return mocks.NewFakeDb("someConnectionString")
+	})
+	cont.AddAutowiredNewMethod("book_finder", mocks.NewBookFinder)
+	cont.AddAutowiredNewMethod("book_storage", mocks.NewBookStorage)
+	cont.AddNewMethod("book_creator", func() mocks.BookCreator {
+		return mocks.BookCreator{}
+	})
+
+	bookFinder, err := Resolve[mocks.BookFinder](cont, "book_finder")
+	assertNoError(err, t)
+
+	book, found := bookFinder.FindBook("two")
+	if !found || book.Title != "SecondBook" {
+		t.Error("Autowired book finder should find a book 'two'")
+	}
+}
+
+func TestAutowiringByInterface(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("in_memory_cache", mocks.NewInMemoryCache)
+	cont.AddAutowiredNewMethod("cache_manager", mocks.NewCacheManager)
+
+	_, err := cont.GetSecure("cache_manager", true)
+	assertNoError(err, t)
+}
+
+func TestAutowiringWithoutCandidates(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddConstructor("in_memory_cache", func(c Container) (interface{}, error) {
+		return mocks.NewInMemoryCache(), nil
+	})
+	cont.AddAutowiredNewMethod("cache_manager", mocks.NewCacheManager)
+
+	_, err := cont.GetSecure("cache_manager", true)
+	assertErrorText(
+		"No service of type 'mocks.Cache' is declared for the argument 1 of the Constr function [check 'cache_manager' service]",
+		err,
+		t,
+	)
+}
+
+func TestAutowiringWithSeveralCandidates(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("in_memory_cache", mocks.NewInMemoryCache)
+	cont.AddNewMethod("another_cache", mocks.NewInMemoryCache)
+	cont.AddAutowiredNewMethod("cache_manager", mocks.NewCacheManager)
+
+	_, err := cont.GetSecure("cache_manager", true)
+	assertErrorText(
+		"Several services of type 'mocks.Cache' are declared for the argument 1 of the Constr function: another_cache, in_memory_cache [check 'cache_manager' service]",
+		err,
+		t,
+	)
+}
+
+func TestAutowiringSkipsVariadicArguments(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("domain", func() string {
+		return "http://example.me"
+	})
+	cont.AddAutowiredNewMethod("url_provider", mocks.NewUrlProviderWithDomain)
+
+	urlProvider := cont.Get("url_provider", true).(mocks.UrlProvider)
+	if len(urlProvider.GetUrls()) != 0 {
+		t.Errorf("No urls are expected in the autowired url provider, but got %v", urlProvider.GetUrls())
+	}
+}
+
+func TestAutowiringOfNonFunction(t *testing.T) {
+	cont := NewRuntimeContainer()
+	err := cont.AddAutowiredNewMethod("book", mocks.Book{})
+	assertErrorText("A function is expected rather than 'struct' [check 'book' service]", err, t)
+}
+
+func TestAutowiringWithConfig(t *testing.T) {
+	tree := Tree{
+		Node{ID: "in_memory_cache", NewFunc: mocks.NewInMemoryCache},
+		Node{ID: "cache_manager", NewFunc: mocks.NewCacheManager, Autowire: true},
+	}
+
+	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfig(tree)
+	assertNoError(err, t)
+
+	_, err = cont.GetSecure("cache_manager", true)
+	assertNoError(err, t)
+}
+
+func TestAutowiringConfigValidation(t *testing.T) {
+	tree := Tree{
+		Node{ID: "in_memory_cache", NewFunc: mocks.NewInMemoryCache},
+		Node{
+			ID:           "cache_manager",
+			NewFunc:      mocks.NewCacheManager,
+			ServiceNames: Services{"in_memory_cache"},
+			Autowire:     true,
+		},
+		Node{ID: "book", Autowire: true},
+	}
+
+	err := ValidateConfigSecure(tree)
+	assertErrorText(
+		"Services list should be empty for an autowired new func, see 'Node: {ID: cache_manager; ServiceNames: [in_memory_cache]; Event: {Name: ; Service: ;}; Observer: {Name: ; Event: ;}}';\n"+
+			"A new or constructor function are expected but none was declared [check 'book' service];\n"+
+			"Autowiring should be defined with a non empty new func, see 'Node: {ID: book; ServiceNames: []; Event: {Name: ; Service: ;}; Observer: {Name: ; Event: ;}}'",
+		err,
+		t,
+	)
+}
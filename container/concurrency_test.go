@@ -0,0 +1,150 @@
+package container
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+const goroutinesCount = 300
+
+func runConcurrently(count int, f func(i int)) {
+	wg := sync.WaitGroup{}
+	wg.Add(count)
+	for i := 0; i < count; i++ {
+		go func(i int) {
+			defer wg.Done()
+			f(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestCachedServiceIsCreatedOnceUnderContention(t *testing.T) {
+	var dbCreationsCount int32
+	cont := NewRuntimeContainer()
+	cont.AddConstructor("connection_string", func(c Container) (interface{}, error) {
+		return "someConnectionString", nil
+	})
+	cont.AddConstructor("db", func(c Container) (interface{}, error) {
+		atomic.AddInt32(&dbCreationsCount, 1)
+		time.Sleep(time.Millisecond * 10)
+		return mocks.NewFakeDb(c.Get("connection_string", true).(string)), nil
+	})
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	cont.AddNewMethod("authors_storage", mocks.NewAuthorsStorage, "db")
+
+	dbs := make([]*mocks.FakeDb, goroutinesCount)
+	runConcurrently(goroutinesCount, func(i int) {
+		serviceName := "book_storage"
+		if i%2 == 0 {
+			serviceName = "authors_storage"
+		}
+
+		_, err := cont.GetSecure(serviceName, true)
+		assertNoError(err, t)
+
+		dbs[i] = cont.Get("db", true).(*mocks.FakeDb)
+	})
+
+	if dbCreationsCount != 1 {
+		t.Errorf("The service 'db' should be created once, but it was created %d times", dbCreationsCount)
+	}
+
+	for _, db := range dbs {
+		if db != dbs[0] {
+			t.Error("All goroutines should receive the same instance of the 'db' service")
+			break
+		}
+	}
+}
+
+func TestNoFalseCyclesUnderContention(t *testing.T) {
+	cont := CreateContainer()
+
+	serviceNames := []string{"book_finder", "book_link_provider", "book_downloader", "statistics_gateway", "authors_storage"}
+	runConcurrently(goroutinesCount, func(i int) {
+		_, err := cont.GetSecure(serviceNames[i%len(serviceNames)], true)
+		assertNoError(err, t)
+	})
+}
+
+func TestNonCachedServicesUnderContention(t *testing.T) {
+	cont := CreateContainer()
+
+	runConcurrently(goroutinesCount, func(i int) {
+		var bookShelve mocks.BookShelve
+		err := cont.ScanSecure("book_shelve", i%2 == 0, &bookShelve)
+		assertNoError(err, t)
+
+		_, err = cont.GetSecure("book_downloader", false)
+		assertNoError(err, t)
+	})
+}
+
+func TestCyclesAreDetectedUnderContention(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddConstructor("rolesProvider", func(c Container) (interface{}, error) {
+		time.Sleep(time.Millisecond)
+		return c.GetSecure("userProvider", true)
+	})
+	cont.AddConstructor("userProvider", func(c Container) (interface{}, error) {
+		time.Sleep(time.Millisecond)
+		return c.GetSecure("rolesProvider", true)
+	})
+
+	done := make(chan bool)
+	go func() {
+		runConcurrently(goroutinesCount, func(i int) {
+			serviceName := "rolesProvider"
+			if i%2 == 0 {
+				serviceName = "userProvider"
+			}
+
+			_, err := cont.GetSecure(serviceName, true)
+			if err == nil || !strings.Contains(err.Error(), "Detected dependencies' cycle") {
+				t.Errorf("A cycle error is expected, but '%v' is returned", err)
+			}
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Fatal("Concurrent resolution of cyclic dependencies is deadlocked")
+	}
+}
+
+func TestRegistrationAlongsideResolution(t *testing.T) {
+	cont := CreateContainer()
+
+	runConcurrently(goroutinesCount, func(i int) {
+		serviceName := fmt.Sprintf("service_%d", i)
+		switch i % 4 {
+		case 0:
+			err := cont.AddConstructor(serviceName, func(c Container) (interface{}, error) {
+				return c.Get("book_storage", true), nil
+			})
+			assertNoError(err, t)
+		case 1:
+			err := cont.SetNewMethod(serviceName, mocks.NewBookFinder, "book_storage", "book_creator")
+			assertNoError(err, t)
+		case 2:
+			cont.SetConstructor("book_creator", func(c Container) (interface{}, error) {
+				return mocks.BookCreator{}, nil
+			})
+		default:
+			_, err := cont.GetSecure("book_finder", i%3 == 0)
+			assertNoError(err, t)
+		}
+	})
+
+	err := cont.Check()
+	assertNoError(err, t)
+}
@@ -65,14 +65,38 @@ type Node struct {
 	Constr        Constructor
 	NewFunc       interface{}
 	ServiceNames  Services
+	Autowire      bool
+	Lifetime      Lifetime
 	Ev            Event
 	Ob            Observer
 	Parameters    map[string]interface{}
 	ParamProvider ParametersProvider
 	GarbageFunc   GarbageCollectorFunc
+	//Decorates is the id of a service wrapped by the NewFunc of the node, see RuntimeContainer.Decorate
+	Decorates string
+	//Tags of the service like "name" or "name,priority=10", see RuntimeContainer.AddTag
+	Tags []string
+	//AliasOf is the id of a service exposed under the ID of the node or bound to the Interface, see RuntimeContainer.Alias
+	AliasOf string
+	//Interface is a nil pointer to an interface bound to the AliasOf service, e.g. (*Logger)(nil), see Bind
+	Interface interface{}
+	//Struct is a prototype of a struct service with fields filled by inject tags, see RuntimeContainer.AddStruct
+	Struct interface{}
 }
 
 func (n Node) String() string {
+	if n.Decorates != "" {
+		return fmt.Sprintf("Node: {Decorates: %s; ServiceNames: %s}", n.Decorates, n.ServiceNames)
+	}
+
+	if n.Interface != nil {
+		return fmt.Sprintf("Node: {Interface: %T; AliasOf: %s}", n.Interface, n.AliasOf)
+	}
+
+	if n.AliasOf != "" {
+		return fmt.Sprintf("Node: {ID: %s; AliasOf: %s}", n.ID, n.AliasOf)
+	}
+
 	return fmt.Sprintf(
 		"Node: {ID: %s; ServiceNames: %s; Event: %s; Observer: %s}",
 		n.ID,
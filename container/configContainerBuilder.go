@@ -1,5 +1,7 @@
 package container
 
+import "reflect"
+
 //RuntimeContainerBuilder builds a Runtime container
 type RuntimeContainerBuilder struct{}
 
@@ -28,13 +30,45 @@ func (rc RuntimeContainerBuilder) BuildContainerFromConfigSecure(trees ...Tree)
 
 func (rc RuntimeContainerBuilder) addTreeToContainer(tree Tree, c *RuntimeContainer) (err error) {
 	errors := []error{}
+	err = rc.addParameters(tree, c)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
 	for _, node := range tree {
+		if node.Decorates != "" || node.AliasOf != "" {
+			continue
+		}
+
 		err = rc.addNode(node, c)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 
+	//aliases and decorators are added when all services are declared, so they can be declared in any order
+	for _, node := range tree {
+		if node.AliasOf == "" {
+			continue
+		}
+
+		err = rc.addAlias(node, c)
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	for _, node := range tree {
+		if node.Decorates == "" {
+			continue
+		}
+
+		err = c.Decorate(node.Decorates, node.NewFunc, node.ServiceNames...)
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+
 	return mergeErrors(errors)
 }
 
@@ -42,7 +76,12 @@ func (rc RuntimeContainerBuilder) addNode(node Node, container *RuntimeContainer
 	var err error
 	errs := []error{}
 
-	if node.NewFunc != nil {
+	if node.NewFunc != nil && node.Autowire {
+		err = container.AddAutowiredNewMethod(node.ID, node.NewFunc)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	} else if node.NewFunc != nil {
 		err = rc.addNewFunc(node.ID, node.NewFunc, node.ServiceNames, container)
 		if err != nil {
 			errs = append(errs, err)
@@ -56,6 +95,13 @@ func (rc RuntimeContainerBuilder) addNode(node Node, container *RuntimeContainer
 		}
 	}
 
+	if node.Struct != nil {
+		err = container.AddStruct(node.ID, node.Struct)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if node.Ev.Service != "" {
 		rc.addEvent(node.Ev.Name, node.Ev.Service, container)
 	}
@@ -67,15 +113,12 @@ func (rc RuntimeContainerBuilder) addNode(node Node, container *RuntimeContainer
 		}
 	}
 
-	if node.Parameters != nil {
-		err = rc.addParameters(node.Parameters, container)
-		if err != nil {
-			errs = append(errs, err)
-		}
+	if node.Lifetime != DefaultLifetime {
+		container.SetLifetime(node.ID, node.Lifetime)
 	}
 
-	if node.ParamProvider != nil {
-		err = rc.addParametersProvider(node.ParamProvider, container)
+	for _, tag := range node.Tags {
+		err = container.AddTag(node.ID, tag)
 		if err != nil {
 			errs = append(errs, err)
 		}
@@ -88,6 +131,14 @@ func (rc RuntimeContainerBuilder) addNode(node Node, container *RuntimeContainer
 	return mergeErrors(errs)
 }
 
+func (rc RuntimeContainerBuilder) addAlias(node Node, container *RuntimeContainer) error {
+	if node.Interface != nil {
+		return container.bind(reflect.TypeOf(node.Interface).Elem(), node.AliasOf)
+	}
+
+	return container.Alias(node.ID, node.AliasOf)
+}
+
 func (rc RuntimeContainerBuilder) addNewFunc(serviceID string, newFunc interface{}, serviceNames []string, container *RuntimeContainer) error {
 	return container.AddNewMethod(serviceID, newFunc, serviceNames...)
 }
@@ -109,13 +160,20 @@ func (rc RuntimeContainerBuilder) addObserver(
 	return container.AddDependencyObserver(eventName, observerID, callback)
 }
 
-func (rc RuntimeContainerBuilder) addParameters(parameters map[string]interface{}, container *RuntimeContainer) error {
-	return RegisterParameters(container, parameters)
-}
+//addParameters registers parameters of all nodes at once, so that placeholders can refer to parameters of any node
+func (rc RuntimeContainerBuilder) addParameters(tree Tree, container *RuntimeContainer) error {
+	parametersMaps := []interface{}{}
+	for _, node := range tree {
+		if node.Parameters != nil {
+			parametersMaps = append(parametersMaps, node.Parameters)
+		}
+
+		if node.ParamProvider != nil {
+			parametersMaps = append(parametersMaps, node.ParamProvider.GetItems())
+		}
+	}
 
-func (rc RuntimeContainerBuilder) addParametersProvider(parametersProvider ParametersProvider, container *RuntimeContainer) error {
-	parameters := parametersProvider.GetItems()
-	return rc.addParameters(parameters, container)
+	return RegisterParameters(container, parametersMaps...)
 }
 
 func (rc RuntimeContainerBuilder) mergeTrees(trees []Tree) Tree {
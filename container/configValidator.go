@@ -7,6 +7,21 @@ import (
 )
 
 func validateNode(node Node, errCollection *[]error, tree Tree) {
+	if node.Decorates != "" {
+		validateDecorator(node, errCollection)
+		return
+	}
+
+	if node.AliasOf != "" || node.Interface != nil {
+		validateAlias(node, errCollection)
+		return
+	}
+
+	if node.Struct != nil {
+		validateStruct(node, errCollection)
+		return
+	}
+
 	if node.NewFunc != nil {
 		validateNewFunc(node, errCollection)
 		return
@@ -24,6 +39,21 @@ func validateNode(node Node, errCollection *[]error, tree Tree) {
 		return
 	}
 
+	if node.Autowire && node.NewFunc == nil {
+		registerNewErrorInCollection(errCollection, "Autowiring should be defined with a non empty new func, see '%s'", node)
+		return
+	}
+
+	if node.Lifetime != DefaultLifetime {
+		registerNewErrorInCollection(errCollection, "Lifetime should be defined with a non empty new func or constructor, see '%s'", node)
+		return
+	}
+
+	if len(node.Tags) > 0 {
+		registerNewErrorInCollection(errCollection, "Tags should be defined with a non empty new func or constructor, see '%s'", node)
+		return
+	}
+
 	if node.Ob.Name != "" || node.Ob.Callback != nil || node.Ob.Event != "" {
 		validateObserverDefinition(node, errCollection)
 		return
@@ -59,12 +89,110 @@ func validateNewFunc(node Node, errCollection *[]error) {
 	var err error
 	reflectedNewMethod := reflect.ValueOf(node.NewFunc)
 
-	err = assertFunctionDeclaration(reflectedNewMethod, len(node.ServiceNames), node.String())
-	addErrorToCollection(errCollection, err)
+	if node.Autowire {
+		validateAutowiredNewFunc(node, reflectedNewMethod, errCollection)
+	} else {
+		err = assertNewMethodDeclaration(reflectedNewMethod, len(node.ServiceNames), node.String())
+		addErrorToCollection(errCollection, err)
+	}
 
 	err = validateConstructorReturnValues(reflectedNewMethod, node.ID)
 	addErrorToCollection(errCollection, err)
 	assertServiceIDIsNotEmpty(node, errCollection, "The new function should be provided with a service id, see '%s'")
+	validateTags(node, errCollection)
+}
+
+func validateAutowiredNewFunc(node Node, reflectedNewMethod reflect.Value, errCollection *[]error) {
+	if len(node.ServiceNames) > 0 {
+		registerNewErrorInCollection(errCollection, "Services list should be empty for an autowired new func, see '%s'", node)
+	}
+
+	if !isFunction(reflectedNewMethod) {
+		err := assertFunctionDeclaration(reflectedNewMethod, 0, node.String())
+		addErrorToCollection(errCollection, err)
+	}
+}
+
+func validateDecorator(node Node, errCollection *[]error) {
+	if node.NewFunc == nil {
+		registerNewErrorInCollection(errCollection, "The decorator should be defined with a non empty new func, see '%s'", node)
+		return
+	}
+
+	isDecoratorOnly := node.ID == "" &&
+		node.Constr == nil &&
+		!node.Autowire &&
+		node.Lifetime == DefaultLifetime &&
+		node.Ev.IsEmpty() &&
+		node.Ob.IsEmpty() &&
+		node.Parameters == nil &&
+		node.ParamProvider == nil &&
+		node.GarbageFunc == nil &&
+		len(node.Tags) == 0 &&
+		node.AliasOf == "" &&
+		node.Interface == nil &&
+		node.Struct == nil
+	if !isDecoratorOnly {
+		registerNewErrorInCollection(errCollection, "The decorator node should contain only a new func and services, see '%s'", node)
+	}
+
+	_, err := newServiceDecorator(node.NewFunc, node.ServiceNames, node.Decorates)
+	addErrorToCollection(errCollection, err)
+}
+
+func validateAlias(node Node, errCollection *[]error) {
+	if node.AliasOf == "" {
+		registerNewErrorInCollection(errCollection, "An interface should be bound to a service defined in AliasOf, see '%s'", node)
+		return
+	}
+
+	if node.ID == "" && node.Interface == nil {
+		registerNewErrorInCollection(errCollection, "An alias should be defined with a non empty id or interface, see '%s'", node)
+	}
+
+	if node.Interface != nil {
+		interfaceType := reflect.TypeOf(node.Interface)
+		if interfaceType.Kind() != reflect.Ptr || interfaceType.Elem().Kind() != reflect.Interface {
+			registerNewErrorInCollection(
+				errCollection,
+				"The interface should be defined as a nil pointer to an interface, e.g. (*Logger)(nil), see '%s'",
+				node,
+			)
+		}
+	}
+
+	isAliasOnly := (node.ID == "" || node.Interface == nil) &&
+		node.Constr == nil &&
+		node.NewFunc == nil &&
+		len(node.ServiceNames) == 0 &&
+		!node.Autowire &&
+		node.Lifetime == DefaultLifetime &&
+		node.Ev.IsEmpty() &&
+		node.Ob.IsEmpty() &&
+		node.Parameters == nil &&
+		node.ParamProvider == nil &&
+		node.GarbageFunc == nil &&
+		len(node.Tags) == 0 &&
+		node.Struct == nil
+	if !isAliasOnly {
+		registerNewErrorInCollection(errCollection, "The alias node should contain only an id or an interface and AliasOf, see '%s'", node)
+	}
+}
+
+func validateStruct(node Node, errCollection *[]error) {
+	assertNewIsEmpty(node, errCollection)
+	assertConstructorIsEmpty(node, errCollection)
+	assertEventIsEmpty(node, errCollection)
+	assertObserverIsEmpty(node, errCollection)
+
+	if len(node.ServiceNames) > 0 || node.Autowire {
+		registerNewErrorInCollection(errCollection, "Services of a struct should be defined with inject tags, see '%s'", node)
+	}
+
+	_, _, err := getInjectedStruct(node.Struct, node.ID)
+	addErrorToCollection(errCollection, err)
+	assertServiceIDIsNotEmpty(node, errCollection, "The struct should be provided with a service id, see '%s'")
+	validateTags(node, errCollection)
 }
 
 func validateConstrFunc(node Node, errCollection *[]error) {
@@ -72,6 +200,14 @@ func validateConstrFunc(node Node, errCollection *[]error) {
 	assertEventIsEmpty(node, errCollection)
 	assertObserverIsEmpty(node, errCollection)
 	assertServiceIDIsNotEmpty(node, errCollection, "The constructor function should be provided with a non empty service id, see '%s'")
+	validateTags(node, errCollection)
+}
+
+func validateTags(node Node, errCollection *[]error) {
+	for _, tag := range node.Tags {
+		_, err := parseTag(node.ID, tag)
+		addErrorToCollection(errCollection, err)
+	}
 }
 
 func validateObserverDefinition(node Node, errCollection *[]error) {
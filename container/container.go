@@ -1,5 +1,7 @@
 package container
 
+import "reflect"
+
 //Container main interface for registering and fetching Services
 type Container interface {
 	AddConstructor(id string, constructor Constructor) error
@@ -17,11 +19,28 @@ type Container interface {
 	SetNewMethod(id string, typedConstructor interface{}, constructorArgumentNames ...string) error
 }
 
+//Introspector lists and describes declared services without creating them, it's implemented by the RuntimeContainer
+type Introspector interface {
+	Services() []string
+	Describe(id string) (ServiceDescription, error)
+	Instantiated() []string
+}
+
 //MergeableContainer containers that support merging
 type MergeableContainer interface {
 	Merge(c MergeableContainer) error
 	getConstructors() map[string]Constructor
 	getNewFuncConstructors() map[string]NewFuncConstructor
+	getServiceTypes() map[string]reflect.Type
+	getNewMethods() map[string]*newMethodDeclaration
+	getStructFields() map[string][]injectedField
+	getParameters() map[string]interface{}
+	getDecorators() map[string][]*serviceDecorator
+	getTags() map[string][]TaggedService
+	getAliases() map[string]string
+	getBindings() map[reflect.Type]string
+	getSources() map[string]uintptr
+	getLifetimes() map[string]Lifetime
 	getCache() dependencyCache
-	getEventsContainer() EventsContainer
+	getEventsContainer() *EventsContainer
 }
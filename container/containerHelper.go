@@ -2,12 +2,23 @@ package container
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
 )
 
-//RegisterParameters adds scalar parameter values as dependencies
+type parameter struct {
+	name  string
+	value interface{}
+}
+
+//RegisterParameters adds scalar parameter values as dependencies. String values might refer to other parameters
+//as "%param_name%" and to environment variables as "${ENV_NAME}" or "${ENV_NAME:-default value}", "%%" gives a
+//single percent sign. Referenced parameters are taken from the provided maps or from the container
 func RegisterParameters(c Container, dependenciesMaps ...interface{}) error {
 	errs := []error{}
+	parameters := []parameter{}
+	parametersByName := map[string]interface{}{}
 	for _, currMap := range dependenciesMaps {
 		reflectedMap := reflect.ValueOf(currMap)
 		if reflectedMap.Kind() != reflect.Map {
@@ -15,7 +26,13 @@ func RegisterParameters(c Container, dependenciesMaps ...interface{}) error {
 			continue
 		}
 
-		for _, mapKey := range reflectedMap.MapKeys() {
+		mapKeys := reflectedMap.MapKeys()
+		//sorted keys make errors of parameters predictable
+		sort.Slice(mapKeys, func(i, j int) bool {
+			return fmt.Sprint(mapKeys[i]) < fmt.Sprint(mapKeys[j])
+		})
+
+		for _, mapKey := range mapKeys {
 			if mapKey.Kind() != reflect.String {
 				errs = append(
 					errs,
@@ -30,12 +47,36 @@ func RegisterParameters(c Container, dependenciesMaps ...interface{}) error {
 				continue
 			}
 
-			err := c.AddConstructor(serviceName, func(c Container) (interface{}, error) {
-				return elem.Interface(), nil
-			})
-			if err != nil {
+			parameters = append(parameters, parameter{name: serviceName, value: elem.Interface()})
+			if _, exists := parametersByName[serviceName]; !exists {
+				parametersByName[serviceName] = elem.Interface()
+			}
+		}
+	}
+
+	resolver := newParametersResolver(c, parametersByName)
+	reportedErrs := map[error]bool{}
+	for _, param := range parameters {
+		value, err := resolver.resolve(param.name)
+		if err != nil {
+			//parameters referring to a failed one share its error, so it's reported once
+			if !reportedErrs[err] {
+				reportedErrs[err] = true
 				errs = append(errs, err)
 			}
+			continue
+		}
+
+		err = c.AddConstructor(param.name, func(c Container) (interface{}, error) {
+			return value, nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if registeredParameters, isParametersContainer := c.(parametersContainer); isParametersContainer {
+			registeredParameters.addParameter(param.name, value)
 		}
 	}
 	return mergeErrors(errs)
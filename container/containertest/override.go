@@ -0,0 +1,21 @@
+//Package containertest provides helpers for tests of applications which declare their services in the container
+package containertest
+
+import (
+	"testing"
+
+	"github.com/breathbath/gotainer/container"
+)
+
+//Override replaces the service identified by id with the fake for the duration of the test, cached services which
+//depend on it are created again with the fake. The original service is restored when the test and its subtests finish,
+//the test fails immediately if the service cannot be overridden
+func Override(t testing.TB, c *container.RuntimeContainer, id string, fake interface{}) {
+	t.Helper()
+
+	restore, err := c.Override(id, fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(restore)
+}
@@ -0,0 +1,28 @@
+package containertest
+
+import (
+	"testing"
+
+	"github.com/breathbath/gotainer/container"
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func TestOverride(t *testing.T) {
+	cont := container.NewRuntimeContainer()
+	cont.AddNewMethod("db", func() *mocks.FakeDb { return &mocks.FakeDb{} })
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	originalStorage := cont.Get("book_storage", true)
+
+	fakeDb := &mocks.FakeDb{}
+	t.Run("with fake db", func(t *testing.T) {
+		Override(t, cont, "db", fakeDb)
+
+		if cont.Get("book_storage", true) != mocks.NewBookStorage(fakeDb) {
+			t.Error("The book storage should be created with the fake db")
+		}
+	})
+
+	if cont.Get("book_storage", true) != originalStorage {
+		t.Error("The original book storage should be restored after the test")
+	}
+}
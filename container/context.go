@@ -0,0 +1,88 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+//ContextConstructor func to return a Service or an error, it receives the context of the current resolution
+type ContextConstructor func(ctx context.Context, c Container) (interface{}, error)
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+//AddContextConstructor registers a Callback which receives the context of the resolution, fails if id already exists
+func (rc *RuntimeContainer) AddContextConstructor(id string, constructor ContextConstructor) error {
+	err := rc.AddConstructor(id, wrapContextConstructor(constructor))
+	if err != nil {
+		return err
+	}
+
+	rc.setSource(id, reflect.ValueOf(constructor).Pointer())
+
+	return nil
+}
+
+//SetContextConstructor adds a new context aware service if it's not existing or overrides an existing one
+func (rc *RuntimeContainer) SetContextConstructor(id string, constructor ContextConstructor) {
+	rc.SetConstructor(id, wrapContextConstructor(constructor))
+	rc.setSource(id, reflect.ValueOf(constructor).Pointer())
+}
+
+//GetContext fetches a cached Service and passes the context to all constructors which take one,
+//the resolution is aborted as soon as the context is done
+func (rc *RuntimeContainer) GetContext(ctx context.Context, id string) (interface{}, error) {
+	return newContextResolution(ctx, rc).GetSecure(id, true)
+}
+
+//ScanContext copies a cached Service into a typed destination (its a pointer reference), the context is passed
+//in the same way as in GetContext
+func (rc *RuntimeContainer) ScanContext(ctx context.Context, id string, dest interface{}) error {
+	baseValue, err := rc.GetContext(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return copySourceVariableToDestinationVariable(baseValue, dest, id)
+}
+
+//ContextOf gives the context of the resolution which calls a Constructor, it's context.Background()
+//if the service is fetched without a context
+func ContextOf(c Container) context.Context {
+	if r, ok := c.(*resolution); ok {
+		return r.ctx
+	}
+
+	return context.Background()
+}
+
+func wrapContextConstructor(constructor ContextConstructor) Constructor {
+	return func(c Container) (interface{}, error) {
+		return constructor(ContextOf(c), c)
+	}
+}
+
+//contextArgumentsCount gives 1 if the first argument of a New method is a context, such an argument
+//is not declared as a dependency as it is provided from the resolution
+func contextArgumentsCount(newMethodType reflect.Type) int {
+	if newMethodType.NumIn() > 0 && newMethodType.In(0) == contextType {
+		return 1
+	}
+
+	return 0
+}
+
+//skippedContextArgumentsCount does the same as contextArgumentsCount for a New method with declared dependencies,
+//but a context is taken as a dependency if all arguments of a non variadic New method are declared, so configs which
+//pass a context service by id keep working
+func skippedContextArgumentsCount(newMethodType reflect.Type, dependenciesCount int) int {
+	if !newMethodType.IsVariadic() && dependenciesCount == newMethodType.NumIn() {
+		return 0
+	}
+
+	return contextArgumentsCount(newMethodType)
+}
+
+func newAbortedResolutionError(ctx context.Context, id string) error {
+	return fmt.Errorf("Resolution is aborted: %w [check '%s' service]", ctx.Err(), id)
+}
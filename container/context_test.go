@@ -0,0 +1,169 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+type contextKey string
+
+func TestContextIsPassedToNewMethods(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddConstructor("connection_string", func(c Container) (interface{}, error) {
+		return "someConnectionString", nil
+	})
+	err := cont.AddNewMethod("db", func(ctx context.Context, connectionString string) (*mocks.FakeDb, error) {
+		if ctx.Value(contextKey("tenant")) != "library" {
+			return nil, errors.New("Tenant is not provided")
+		}
+		return mocks.NewFakeDb(connectionString), nil
+	}, "connection_string")
+	assertNoError(err, t)
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+
+	ctx := context.WithValue(context.Background(), contextKey("tenant"), "library")
+
+	var bookStorage mocks.BookStorage
+	err = cont.ScanContext(ctx, "book_storage", &bookStorage)
+	assertNoError(err, t)
+
+	_, err = cont.GetSecure("book_storage", false)
+	assertErrorText("book_storage -> db: Tenant is not provided [check 'db' service]", err, t)
+}
+
+func TestContextServiceDeclaredAsDependency(t *testing.T) {
+	newTenant := func(ctx context.Context, suffix string) string {
+		tenant, _ := ctx.Value(contextKey("tenant")).(string)
+		return tenant + suffix
+	}
+
+	cont := NewRuntimeContainer()
+	cont.AddConstructor("tenant_context", func(c Container) (interface{}, error) {
+		return context.WithValue(context.Background(), contextKey("tenant"), "library"), nil
+	})
+	cont.AddConstructor("suffix", func(c Container) (interface{}, error) {
+		return "_tenant", nil
+	})
+	err := cont.AddNewMethod("declared_tenant", newTenant, "tenant_context", "suffix")
+	assertNoError(err, t)
+	err = cont.AddNewMethod("resolved_tenant", newTenant, "suffix")
+	assertNoError(err, t)
+
+	ctx := context.WithValue(context.Background(), contextKey("tenant"), "shop")
+	declaredTenant, err := cont.GetContext(ctx, "declared_tenant")
+	assertNoError(err, t)
+	resolvedTenant, err := cont.GetContext(ctx, "resolved_tenant")
+	assertNoError(err, t)
+	if declaredTenant != "library_tenant" || resolvedTenant != "shop_tenant" {
+		t.Errorf(
+			"The declared context service and the resolution context are expected, but '%v' and '%v' are given",
+			declaredTenant,
+			resolvedTenant,
+		)
+	}
+
+	cont.Freeze()
+	frozenTenant, err := cont.GetSecure("declared_tenant", false)
+	assertNoError(err, t)
+	if frozenTenant != "library_tenant" {
+		t.Errorf("The declared context service is expected in a frozen container, but '%v' is given", frozenTenant)
+	}
+}
+
+func TestContextConstructor(t *testing.T) {
+	cont := NewRuntimeContainer()
+	err := cont.AddContextConstructor("deadline", func(ctx context.Context, c Container) (interface{}, error) {
+		deadline, _ := ctx.Deadline()
+		return deadline, nil
+	})
+	assertNoError(err, t)
+
+	err = cont.AddContextConstructor("deadline", func(ctx context.Context, c Container) (interface{}, error) {
+		return nil, nil
+	})
+	assertErrorText("Detected duplicated dependency declaration 'deadline'", err, t)
+
+	expectedDeadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), expectedDeadline)
+	defer cancel()
+
+	deadline, err := cont.GetContext(ctx, "deadline")
+	assertNoError(err, t)
+	if !deadline.(time.Time).Equal(expectedDeadline) {
+		t.Errorf("Expected deadline %v but got %v", expectedDeadline, deadline)
+	}
+}
+
+func TestResolutionIsAbortedByCancelledContext(t *testing.T) {
+	cont := NewRuntimeContainer()
+	ctx, cancel := context.WithCancel(context.Background())
+	cont.AddNewMethod("db", func() *mocks.FakeDb {
+		cancel()
+		return mocks.NewFakeDb("someConnectionString")
+	})
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	cont.AddNewMethod("book_creator", func() mocks.BookCreator { return mocks.BookCreator{} })
+	cont.AddNewMethod("book_finder", mocks.NewBookFinder, "book_storage", "book_creator")
+
+	_, err := cont.GetContext(ctx, "book_finder")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("context.Canceled is expected in '%v'", err)
+	}
+	assertErrorText("Resolution is aborted: context canceled [check 'book_creator' service] [check 'book_finder' service]", err, t)
+
+	if _, isCached := cont.cache.Get("book_finder"); isCached {
+		t.Error("The service should not be created by an aborted resolution")
+	}
+}
+
+func TestWaitingForInFlightConstructionIsAborted(t *testing.T) {
+	cont := NewRuntimeContainer()
+	dbIsStarted := make(chan bool)
+	dbIsReleased := make(chan bool)
+	cont.AddNewMethod("db", func() *mocks.FakeDb {
+		close(dbIsStarted)
+		<-dbIsReleased
+		return mocks.NewFakeDb("someConnectionString")
+	})
+
+	go cont.Get("db", true)
+	<-dbIsStarted
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := cont.GetContext(ctx, "db")
+	close(dbIsReleased)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("context.DeadlineExceeded is expected in '%v'", err)
+	}
+}
+
+func TestContextArgumentIsNotADependency(t *testing.T) {
+	newBookStorage := func(ctx context.Context, db *mocks.FakeDb) mocks.BookStorage {
+		return mocks.NewBookStorage(db)
+	}
+
+	cont := NewRuntimeContainer()
+	err := cont.AddNewMethod("book_storage", newBookStorage)
+	assertErrorText("The function requires 1 arguments, but 0 arguments are provided [check 'book_storage' service]", err, t)
+
+	tree := Tree{
+		Node{ID: "db", NewFunc: func() *mocks.FakeDb { return mocks.NewFakeDb("someConnectionString") }},
+		Node{ID: "book_storage", NewFunc: newBookStorage, ServiceNames: Services{"db"}},
+		Node{ID: "autowired_storage", NewFunc: newBookStorage, Autowire: true},
+	}
+
+	err = ValidateGraphSecure(tree)
+	assertNoError(err, t)
+
+	autowiredCont, err := RuntimeContainerBuilder{}.BuildContainerFromConfigSecure(tree)
+	assertNoError(err, t)
+
+	_, err = autowiredCont.GetSecure("autowired_storage", true)
+	assertNoError(err, t)
+}
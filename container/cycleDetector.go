@@ -30,6 +30,26 @@ func (cd *CycleDetector) Reset() {
 	cd.isEnabled = true
 }
 
+//fork copies the current state of the detector for a parallel branch of the DFS
+func (cd *CycleDetector) fork() *CycleDetector {
+	forkedDetector := &CycleDetector{
+		recStack:       make(map[string]bool, len(cd.recStack)),
+		recStackSorted: append([]string{}, cd.recStackSorted...),
+		visited:        make(map[string]bool, len(cd.visited)),
+		cycleDetected:  cd.cycleDetected,
+		cycle:          append([]string{}, cd.cycle...),
+		isEnabled:      cd.isEnabled,
+	}
+	for dep, isInRecStack := range cd.recStack {
+		forkedDetector.recStack[dep] = isInRecStack
+	}
+	for dep, isVisited := range cd.visited {
+		forkedDetector.visited[dep] = isVisited
+	}
+
+	return forkedDetector
+}
+
 //VisitBeforeRecursion starts DFS
 func (cd *CycleDetector) VisitBeforeRecursion(dep string) {
 	if !cd.isEnabled || cd.cycleDetected {
@@ -61,7 +61,8 @@ func TestSelfReferenceFailuresWitDirectDeclaration(t *testing.T) {
 func TestCycleReferencesWithConfigDeclaration(t *testing.T) {
 	defer ExpectPanic(
 		t,
-		"Detected dependencies' cycle: userProvider->roleProvider->userProvider [check 'roleProvider' service] [check 'userProvider' service]",
+		"userProvider -> roleProvider: Detected dependencies' cycle: userProvider->roleProvider->userProvider "+
+			"[check 'roleProvider' service]",
 	)
 
 	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfig(cycleTree)
@@ -85,7 +86,8 @@ func TestCycleReferencesWithNewMethodDeclaration(t *testing.T) {
 	err = cont.Check()
 	ExpectErrorSubmatch(
 		err,
-		"userProvider->roleProvider->userProvider [check 'roleProvider' service] [check 'userProvider' service]",
+		"userProvider -> roleProvider: Detected dependencies' cycle: userProvider->roleProvider->userProvider "+
+			"[check 'roleProvider' service]",
 		t,
 	)
 }
@@ -93,8 +95,10 @@ func TestCycleReferencesWithNewMethodDeclaration(t *testing.T) {
 func TestCycleReferencesWithConstructor(t *testing.T) {
 	defer ExpectPanic(
 		t,
-		"Detected dependencies' cycle: rolesProvider->userProvider->rolesProvider [check 'userProvider' service] [check 'rolesProvider' service]",
-		"Detected dependencies' cycle: userProvider->rolesProvider->userProvider [check 'userProvider' service] [check 'rolesProvider' service]",
+		"rolesProvider -> userProvider: Detected dependencies' cycle: rolesProvider->userProvider->rolesProvider "+
+			"[check 'userProvider' service]",
+		"rolesProvider -> userProvider: Detected dependencies' cycle: userProvider->rolesProvider->userProvider "+
+			"[check 'userProvider' service]",
 	)
 	cont := NewRuntimeContainer()
 
@@ -176,7 +180,8 @@ func TestCycleDetectionWithSecureMethod(t *testing.T) {
 	}
 
 	_, err = cont.GetSecure("userProvider", true)
-	expectedErrorText := "Detected dependencies' cycle: userProvider->roleProvider->userProvider [check 'roleProvider' service] [check 'userProvider' service]"
+	expectedErrorText := "userProvider -> roleProvider: Detected dependencies' cycle: " +
+		"userProvider->roleProvider->userProvider [check 'roleProvider' service]"
 	if err.Error() != expectedErrorText {
 		t.Errorf("Error %s expected but %s was received", expectedErrorText, err.Error())
 	}
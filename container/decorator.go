@@ -0,0 +1,152 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+)
+
+//serviceDecorator wraps a created service with a decorator func which gets the service as the first argument and
+//other services by ids as the rest ones
+type serviceDecorator struct {
+	decorator     reflect.Value
+	boundType     reflect.Type
+	argumentNames []string
+	source        uintptr
+}
+
+//Decorate wraps the service identified by id, which is declared in the container, e.g. with logging, metrics or
+//retries without replacing its constructor. The decorator is a func like func(original T, deps...) T or
+//func(original T, deps...) (T, error), its dependencies are declared by ids in the same way as for AddNewMethod.
+//Decorators are applied in the order of their registration, so the first one wraps the original service and
+//the last one gives the service returned by the container
+func (rc *RuntimeContainer) Decorate(id string, decorator interface{}, decoratorArgumentNames ...string) error {
+	serviceDecorator, err := newServiceDecorator(decorator, decoratorArgumentNames, id)
+	if err != nil {
+		return err
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	err = rc.assertNotFrozen(id)
+	if err != nil {
+		return err
+	}
+
+	_, isConstructor := rc.constructors[id]
+	_, isNewFunc := rc.newFuncConstructors[id]
+	if !isConstructor && !isNewFunc {
+		return fmt.Errorf("Cannot decorate the service '%s' as it is not declared in the container", id)
+	}
+
+	if serviceType, isTyped := rc.serviceTypes[id]; isTyped && !isConstructor {
+		err = serviceDecorator.assertDecorates(serviceType, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	rc.decorators[id] = append(rc.decorators[id], serviceDecorator)
+
+	return nil
+}
+
+func newServiceDecorator(decorator interface{}, argumentNames []string, serviceID string) (*serviceDecorator, error) {
+	reflectedDecorator := reflect.ValueOf(decorator)
+	if !isFunction(reflectedDecorator) || reflectedDecorator.Type().NumIn() == 0 {
+		return nil, fmt.Errorf(
+			"A decorator should be a function with the decorated service as the first argument [check '%s' service]",
+			serviceID,
+		)
+	}
+
+	decoratorType := reflectedDecorator.Type()
+	argumentTypes := make([]reflect.Type, 0, decoratorType.NumIn()-1)
+	for i := 1; i < decoratorType.NumIn(); i++ {
+		argumentTypes = append(argumentTypes, decoratorType.In(i))
+	}
+	resultTypes := make([]reflect.Type, 0, decoratorType.NumOut())
+	for i := 0; i < decoratorType.NumOut(); i++ {
+		resultTypes = append(resultTypes, decoratorType.Out(i))
+	}
+
+	//the bound type is the decorator without its first argument, it's validated and called as a New method
+	boundType := reflect.FuncOf(argumentTypes, resultTypes, decoratorType.IsVariadic())
+	err := assertNewMethodDeclaration(reflect.Zero(boundType), len(argumentNames), serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateConstructorReturnValues(reflectedDecorator, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &serviceDecorator{
+		decorator:     reflectedDecorator,
+		boundType:     boundType,
+		argumentNames: argumentNames,
+		source:        reflectedDecorator.Pointer(),
+	}, nil
+}
+
+//assertDecorates checks that the decorator accepts the declared type of the service and returns a compatible one
+func (sd *serviceDecorator) assertDecorates(serviceType reflect.Type, serviceID string) error {
+	err := assertCompatible(sd.decorator.Type().In(0), serviceType, serviceID, serviceID)
+	if err != nil {
+		return err
+	}
+
+	decoratedType := getNewMethodServiceType(sd.decorator)
+	if !decoratedType.AssignableTo(serviceType) {
+		return fmt.Errorf(
+			"The decorator returns '%s' which cannot be used as '%s' [check '%s' service]",
+			decoratedType,
+			serviceType,
+			serviceID,
+		)
+	}
+
+	return nil
+}
+
+//decorate calls the decorator with the original service and its dependencies fetched from the container
+func (sd *serviceDecorator) decorate(c Container, serviceID string, original interface{}, isCached bool) (interface{}, error) {
+	reflectedOriginal, err := getValidFunctionArgument(sd.decorator.Type().In(0), original, serviceID, serviceID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	boundDecorator := reflect.MakeFunc(sd.boundType, func(arguments []reflect.Value) []reflect.Value {
+		arguments = append([]reflect.Value{reflectedOriginal}, arguments...)
+		if sd.boundType.IsVariadic() {
+			return sd.decorator.CallSlice(arguments)
+		}
+
+		return sd.decorator.Call(arguments)
+	})
+
+	return callNewMethod(boundDecorator, sd.argumentNames, c, serviceID, isCached)
+}
+
+//decorate applies decorators of the service declared in the container in the order of their registration
+func (rc *RuntimeContainer) decorate(r *resolution, id string, service interface{}, isCached bool) (interface{}, error) {
+	rc.mutex.RLock()
+	decorators := rc.decorators[id]
+	rc.mutex.RUnlock()
+
+	var err error
+	for _, decorator := range decorators {
+		service, err = decorator.decorate(r, id, service, isCached)
+		if err != nil {
+			return service, &ConstructorError{
+				ServiceID: id,
+				Cause:     err,
+				Path:      r.path,
+				Source:    getFuncSource(decorator.source),
+			}
+		}
+	}
+
+	return service, nil
+}
@@ -0,0 +1,220 @@
+package container
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+//countingCache is a decorator of a cache which counts cached books
+type countingCache struct {
+	cache mocks.Cache
+	count *int
+}
+
+func (cc countingCache) Cache(book mocks.Book) {
+	*cc.count++
+	cc.cache.Cache(book)
+}
+
+func createContainerWithDecoratedGreeting() *RuntimeContainer {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("greeting", func() string { return "Hello" })
+	cont.AddConstructor("user_name", func(c Container) (interface{}, error) {
+		return "admin", nil
+	})
+	cont.AddConstructor("punctuation", func(c Container) (interface{}, error) {
+		return "!", nil
+	})
+
+	return cont
+}
+
+func TestDecoratorsAreStackedInRegistrationOrder(t *testing.T) {
+	cont := createContainerWithDecoratedGreeting()
+
+	err := cont.Decorate("greeting", func(greeting, userName string) string {
+		return greeting + ", " + userName
+	}, "user_name")
+	assertNoError(err, t)
+
+	err = cont.Decorate("greeting", func(greeting string, punctuation string) (string, error) {
+		return greeting + punctuation, nil
+	}, "punctuation")
+	assertNoError(err, t)
+
+	AssertExpectedDependency(cont, "greeting", "Hello, admin!", t)
+}
+
+func TestDecoratorOfInterface(t *testing.T) {
+	cachedBooksCount := 0
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("cache", func() mocks.Cache { return mocks.NewInMemoryCache() })
+	cont.AddNewMethod("cache_manager", mocks.NewCacheManager, "cache")
+	cont.SetLifetime("cache", Transient)
+
+	err := cont.Decorate("cache", func(cache mocks.Cache) mocks.Cache {
+		return countingCache{cache: cache, count: &cachedBooksCount}
+	})
+	assertNoError(err, t)
+
+	scope := cont.NewScope()
+	for _, c := range []*RuntimeContainer{cont, scope} {
+		cache := c.Get("cache", true).(mocks.Cache)
+		cache.Cache(mocks.Book{})
+	}
+
+	if cachedBooksCount != 2 {
+		t.Errorf("Every created cache should be decorated, but %d books were counted", cachedBooksCount)
+	}
+}
+
+func TestDecoratorErrors(t *testing.T) {
+	cont := createContainerWithDecoratedGreeting()
+
+	err := cont.Decorate("farewell", func(farewell string) string { return farewell })
+	assertErrorText("Cannot decorate the service 'farewell' as it is not declared in the container", err, t)
+
+	err = cont.Decorate("greeting", func() string { return "" })
+	assertErrorText(
+		"A decorator should be a function with the decorated service as the first argument [check 'greeting' service]",
+		err,
+		t,
+	)
+
+	err = cont.Decorate("greeting", func(greeting, userName string) string { return greeting }, "user_name", "punctuation")
+	assertErrorText("The function requires 1 arguments, but 2 arguments are provided [check 'greeting' service]", err, t)
+
+	err = cont.Decorate("greeting", func(greeting int) int { return greeting })
+	assertErrorText(
+		"Cannot use the provided dependency 'greeting' of type 'string' as 'int' in the Constr function call [check 'greeting' service]",
+		err,
+		t,
+	)
+
+	err = cont.Decorate("greeting", func(greeting string) []byte { return []byte(greeting) })
+	assertErrorText("The decorator returns '[]uint8' which cannot be used as 'string' [check 'greeting' service]", err, t)
+
+	err = cont.Decorate("greeting", func(greeting string) (string, error) {
+		return "", errors.New("Greetings are forbidden")
+	})
+	assertNoError(err, t)
+
+	_, err = cont.GetSecure("greeting", true)
+	assertErrorText("Greetings are forbidden [check 'greeting' service]", err, t)
+
+	var constructorErr *ConstructorError
+	if !errors.As(err, &constructorErr) || !strings.Contains(constructorErr.Source, "decorator_test.go:") {
+		t.Errorf("The source of the failed decorator is expected in '%+v'", err)
+	}
+}
+
+func TestDecoratorDependencyCycle(t *testing.T) {
+	cont := createContainerWithDecoratedGreeting()
+	cont.AddNewMethod("welcome_page", func(greeting string) string { return greeting }, "greeting")
+	cont.Decorate("greeting", func(greeting, welcomePage string) string { return welcomePage }, "welcome_page")
+
+	_, err := cont.GetSecure("greeting", true)
+
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("CycleError is expected in '%v'", err)
+	}
+}
+
+func TestDecoratorsInConfig(t *testing.T) {
+	tree := Tree{
+		Node{
+			Decorates:    "greeting",
+			NewFunc:      func(greeting, userName string) string { return greeting + ", " + userName },
+			ServiceNames: Services{"user_name"},
+		},
+		Node{ID: "greeting", NewFunc: func() string { return "Hello" }},
+		Node{Parameters: map[string]interface{}{"user_name": "admin"}},
+		Node{Decorates: "greeting", NewFunc: func(greeting string) string { return greeting + "!" }},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertNoError(err, t)
+
+	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfigSecure(tree)
+	assertNoError(err, t)
+	AssertExpectedDependency(cont, "greeting", "Hello, admin!", t)
+
+	dot, err := ExportGraph(tree, DotFormat)
+	assertNoError(err, t)
+	if !strings.Contains(dot, `"greeting" -> "user_name";`) {
+		t.Errorf("Dependencies of decorators should be exported:\n%s", dot)
+	}
+}
+
+func TestDecoratorsValidation(t *testing.T) {
+	tree := Tree{
+		Node{ID: "greeting", NewFunc: func(welcomePage string) string { return welcomePage }, ServiceNames: Services{"welcome_page"}},
+		Node{ID: "welcome_page", NewFunc: func() string { return "Welcome" }},
+		Node{Decorates: "welcome_page", NewFunc: func(welcomePage, greeting string) string { return welcomePage }, ServiceNames: Services{"greeting"}},
+		Node{Decorates: "greeting", NewFunc: func(greeting int) int { return greeting }},
+		Node{Decorates: "farewell", NewFunc: func(farewell string) string { return farewell }},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertErrorText(
+		"Cannot use the provided dependency 'greeting' of type 'string' as 'int' in the Constr function call [check 'greeting' service];\n"+
+			"Cannot decorate the service 'farewell' as it is not declared in the container;\n"+
+			"Detected dependencies' cycle: greeting->welcome_page->greeting",
+		err,
+		t,
+	)
+
+	err = ValidateConfigSecure(Tree{
+		Node{ID: "greeting", Decorates: "welcome_page", NewFunc: func(welcomePage string) string { return welcomePage }},
+		Node{Decorates: "welcome_page"},
+	})
+	assertErrorText(
+		"The decorator node should contain only a new func and services, see 'Node: {Decorates: welcome_page; ServiceNames: []}';\n"+
+			"The decorator should be defined with a non empty new func, see 'Node: {Decorates: welcome_page; ServiceNames: []}'",
+		err,
+		t,
+	)
+}
+
+func TestLoadDecoratorsFromYaml(t *testing.T) {
+	registry := createFuncRegistry(t)
+	err := registry.RegisterFunc("newLoggedStorage", func(bs mocks.BookStorage, logger mocks.NullLogger) mocks.BookStorage {
+		return bs
+	})
+	assertNoError(err, t)
+
+	tree, err := LoadTree([]byte(`
+parameters:
+  connection_string: someConnectionString
+services:
+  - id: db
+    new: mocks.NewFakeDb
+    services: [connection_string]
+  - decorates: book_storage
+    new: newLoggedStorage
+    services: [logger]
+  - id: book_storage
+    new: mocks.NewBookStorage
+    services: [db]
+  - id: logger
+    constructor: newLogger
+`), "services.yaml", registry)
+	assertNoError(err, t)
+
+	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfigSecure(tree)
+	assertNoError(err, t)
+
+	_, err = cont.GetSecure("book_storage", true)
+	assertNoError(err, t)
+
+	_, err = LoadTree([]byte(`
+services:
+  - decorates: book_storage
+    new: unknownFunc
+`), "services.yaml", registry)
+	assertErrorText("services.yaml:4: Unknown function 'unknownFunc' [check 'book_storage' service]", err, t)
+}
@@ -0,0 +1,379 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	constructorServiceKind = "constructor"
+	newFuncServiceKind     = "new func"
+	parameterServiceKind   = "parameter"
+	aliasServiceKind       = "alias"
+)
+
+//unresolvedDependencyID takes the place of an argument which autowiring failed to resolve, so the following
+//dependencies stay aligned with arguments of the new func
+const unresolvedDependencyID = ""
+
+//graphService is a service declaration from a config tree, its type is known only for new funcs and parameters,
+//an alias has the aliased service as its only dependency and the bound interface as its type
+type graphService struct {
+	id           string
+	kind         string
+	serviceType  reflect.Type
+	newFunc      reflect.Value
+	dependencies []string
+	autowire     bool
+}
+
+//graphDecorator is a decorator of a service from a config tree, its bound service is the decorator without the first
+//argument which is validated in the same way as a new func
+type graphDecorator struct {
+	serviceID string
+	decorator *serviceDecorator
+	bound     *graphService
+}
+
+//dependencyGraph describes services of a config tree and their relations without creating them
+type dependencyGraph struct {
+	services   map[string]*graphService
+	serviceIDs []string
+	decorators []graphDecorator
+	tags       map[string][]string
+	bindings   map[reflect.Type]string
+	events     []Event
+	observers  []Observer
+}
+
+//newDependencyGraph collects services, events and observers from a valid config tree, autowired
+//dependencies are found by the declared types of new funcs
+func newDependencyGraph(tree Tree) (*dependencyGraph, error) {
+	graph := &dependencyGraph{
+		services: map[string]*graphService{},
+		tags:     map[string][]string{},
+		bindings: map[reflect.Type]string{},
+	}
+	for _, node := range tree {
+		graph.addNode(node)
+	}
+
+	return graph, graph.autowire()
+}
+
+func (g *dependencyGraph) addNode(node Node) {
+	if node.Decorates != "" {
+		decorator, err := newServiceDecorator(node.NewFunc, node.ServiceNames, node.Decorates)
+		if err == nil {
+			g.decorators = append(g.decorators, graphDecorator{
+				serviceID: node.Decorates,
+				decorator: decorator,
+				bound: &graphService{
+					id:           node.Decorates,
+					kind:         newFuncServiceKind,
+					newFunc:      reflect.Zero(decorator.boundType),
+					dependencies: node.ServiceNames,
+				},
+			})
+		}
+		return
+	}
+
+	if node.AliasOf != "" {
+		alias := &graphService{id: node.ID, kind: aliasServiceKind, dependencies: []string{node.AliasOf}}
+		if node.Interface != nil {
+			alias.serviceType = reflect.TypeOf(node.Interface).Elem()
+			alias.id = getInterfaceID(alias.serviceType)
+			g.bindings[alias.serviceType] = alias.id
+		}
+		g.addService(alias)
+		return
+	}
+
+	if node.Struct != nil {
+		g.addStruct(node)
+	}
+
+	if node.NewFunc != nil {
+		reflectedNewFunc := reflect.ValueOf(node.NewFunc)
+		g.addService(&graphService{
+			id:           node.ID,
+			kind:         newFuncServiceKind,
+			serviceType:  getNewMethodServiceType(reflectedNewFunc),
+			newFunc:      reflectedNewFunc,
+			dependencies: node.ServiceNames,
+			autowire:     node.Autowire,
+		})
+	}
+
+	if node.Constr != nil {
+		g.addService(&graphService{id: node.ID, kind: constructorServiceKind})
+	}
+
+	for _, tag := range node.Tags {
+		if taggedService, err := parseTag(node.ID, tag); err == nil {
+			g.tags[taggedService.Tag] = append(g.tags[taggedService.Tag], node.ID)
+		}
+	}
+
+	parameters := map[string]interface{}{}
+	if node.ParamProvider != nil {
+		parameters = node.ParamProvider.GetItems()
+	}
+	for parameterName, parameterValue := range node.Parameters {
+		parameters[parameterName] = parameterValue
+	}
+
+	parameterNames := make([]string, 0, len(parameters))
+	for parameterName := range parameters {
+		parameterNames = append(parameterNames, parameterName)
+	}
+	sort.Strings(parameterNames)
+
+	for _, parameterName := range parameterNames {
+		g.addService(&graphService{
+			id:          parameterName,
+			kind:        parameterServiceKind,
+			serviceType: reflect.TypeOf(parameters[parameterName]),
+		})
+	}
+
+	if !node.Ev.IsEmpty() {
+		g.events = append(g.events, node.Ev)
+	}
+
+	if !node.Ob.IsEmpty() {
+		g.observers = append(g.observers, node.Ob)
+	}
+}
+
+//addStruct adds a struct service as a new func which gets the injected fields as arguments
+func (g *dependencyGraph) addStruct(node Node) {
+	structType, fields, err := getInjectedStruct(node.Struct, node.ID)
+	if err != nil {
+		return
+	}
+
+	fieldTypes := make([]reflect.Type, 0, len(fields))
+	service := &graphService{
+		id:           node.ID,
+		kind:         newFuncServiceKind,
+		serviceType:  reflect.TypeOf(node.Struct),
+		dependencies: make([]string, 0, len(fields)),
+	}
+	for _, field := range fields {
+		fieldTypes = append(fieldTypes, field.fieldType)
+		service.dependencies = append(service.dependencies, field.getDependencyName())
+	}
+	service.newFunc = reflect.Zero(reflect.FuncOf(fieldTypes, []reflect.Type{structType}, false))
+
+	g.addService(service)
+}
+
+func (g *dependencyGraph) addService(service *graphService) {
+	if _, exists := g.services[service.id]; !exists {
+		g.serviceIDs = append(g.serviceIDs, service.id)
+	}
+	g.services[service.id] = service
+}
+
+//autowire fills dependencies of autowired services in the same way as the RuntimeContainer does
+func (g *dependencyGraph) autowire() error {
+	errs := []error{}
+	for _, serviceID := range g.serviceIDs {
+		service := g.services[serviceID]
+		if !service.autowire {
+			continue
+		}
+
+		newFuncType := service.newFunc.Type()
+		argumentsCount := newFuncType.NumIn()
+		if newFuncType.IsVariadic() {
+			argumentsCount--
+		}
+
+		service.dependencies = []string{}
+		for i := contextArgumentsCount(newFuncType); i < argumentsCount; i++ {
+			if interfaceID, isBound := g.bindings[newFuncType.In(i)]; isBound {
+				service.dependencies = append(service.dependencies, interfaceID)
+				continue
+			}
+
+			candidates := g.findServicesByType(newFuncType.In(i), serviceID)
+			switch len(candidates) {
+			case 0:
+				service.dependencies = append(service.dependencies, unresolvedDependencyID)
+				errs = append(errs, fmt.Errorf(
+					"No service of type '%s' is declared for the argument %d of the Constr function [check '%s' service]",
+					newFuncType.In(i),
+					i+1,
+					serviceID,
+				))
+			case 1:
+				service.dependencies = append(service.dependencies, candidates[0])
+			default:
+				service.dependencies = append(service.dependencies, unresolvedDependencyID)
+				errs = append(errs, fmt.Errorf(
+					"Several services of type '%s' are declared for the argument %d of the Constr function: %s [check '%s' service]",
+					newFuncType.In(i),
+					i+1,
+					strings.Join(candidates, ", "),
+					serviceID,
+				))
+			}
+		}
+	}
+
+	return mergeErrors(errs)
+}
+
+//findServicesByType gives sorted ids of new func services which declared type can be used as the expected type
+func (g *dependencyGraph) findServicesByType(expectedType reflect.Type, excludedServiceID string) []string {
+	serviceIDs := []string{}
+	for _, serviceID := range g.serviceIDs {
+		service := g.services[serviceID]
+		if serviceID == excludedServiceID || service.kind != newFuncServiceKind {
+			continue
+		}
+
+		if service.serviceType.AssignableTo(expectedType) {
+			serviceIDs = append(serviceIDs, serviceID)
+		}
+	}
+	sort.Strings(serviceIDs)
+
+	return serviceIDs
+}
+
+//resolveAlias gives the service exposed by the alias or the service itself if it's not an alias, nil is given for
+//aliases of unknown services or cyclic aliases
+func (g *dependencyGraph) resolveAlias(service *graphService) *graphService {
+	knownAliases := map[string]bool{}
+	for service != nil && service.kind == aliasServiceKind {
+		if knownAliases[service.id] {
+			return nil
+		}
+		knownAliases[service.id] = true
+		service = g.services[service.dependencies[0]]
+	}
+
+	return service
+}
+
+//getDependencies gives dependencies of a service followed by dependencies of its decorators, tag references are
+//replaced with ids of the tagged services, optional dependencies which are not declared and arguments which failed
+//autowiring are skipped
+func (g *dependencyGraph) getDependencies(serviceID string) []string {
+	declaredDependencies := append([]string{}, g.services[serviceID].dependencies...)
+	for _, decorator := range g.decorators {
+		if decorator.serviceID == serviceID {
+			declaredDependencies = append(declaredDependencies, decorator.bound.dependencies...)
+		}
+	}
+
+	dependencies := make([]string, 0, len(declaredDependencies))
+	for _, dependencyID := range declaredDependencies {
+		if dependencyID == unresolvedDependencyID {
+			continue
+		}
+
+		if tag, isTagReference := parseTagReference(dependencyID); isTagReference {
+			dependencies = append(dependencies, g.tags[tag]...)
+			continue
+		}
+
+		if optional, isOptional := parseOptionalDependency(dependencyID); isOptional {
+			if _, exists := g.services[optional.id]; exists {
+				dependencies = append(dependencies, optional.id)
+			}
+			continue
+		}
+		dependencies = append(dependencies, dependencyID)
+	}
+
+	return dependencies
+}
+
+//findCycles gives dependency cycles found by the CycleDetector starting from each service in the declaration order,
+//the same cycle reached from different services is given once
+func (g *dependencyGraph) findCycles() [][]string {
+	cycles := [][]string{}
+	knownCycles := map[string]bool{}
+	for _, serviceID := range g.serviceIDs {
+		detector := NewCycleDetector()
+		g.visitDependencies(serviceID, detector, map[string]bool{})
+		if !detector.HasCycle() {
+			continue
+		}
+
+		//the detector gives the whole path from the starting service, the cycle begins with its last service
+		cycle := detector.GetCycle()
+		for i, cyclicServiceID := range cycle {
+			if cyclicServiceID == cycle[len(cycle)-1] {
+				cycle = cycle[i:]
+				break
+			}
+		}
+
+		cycleKey := getCycleKey(cycle)
+		if !knownCycles[cycleKey] {
+			knownCycles[cycleKey] = true
+			cycles = append(cycles, cycle)
+		}
+	}
+
+	return cycles
+}
+
+//visitDependencies walks dependencies of the service depth first until the detector finds a cycle
+func (g *dependencyGraph) visitDependencies(serviceID string, detector *CycleDetector, visited map[string]bool) {
+	detector.VisitBeforeRecursion(serviceID)
+	if detector.HasCycle() || visited[serviceID] {
+		return
+	}
+	visited[serviceID] = true
+
+	if _, exists := g.services[serviceID]; exists {
+		for _, dependencyID := range g.getDependencies(serviceID) {
+			g.visitDependencies(dependencyID, detector, visited)
+			if detector.HasCycle() {
+				return
+			}
+		}
+	}
+
+	detector.VisitAfterRecursion(serviceID)
+}
+
+//getCycleKey identifies a cycle regardless of the service it starts with
+func getCycleKey(cycle []string) string {
+	services := cycle[:len(cycle)-1]
+	start := 0
+	for i, serviceID := range services {
+		if serviceID < services[start] {
+			start = i
+		}
+	}
+
+	return strings.Join(append(append([]string{}, services[start:]...), services[:start]...), "->")
+}
+
+//getUnresolvedArguments gives types of arguments of the autowired service which have no matching service
+func (g *dependencyGraph) getUnresolvedArguments(serviceID string) []reflect.Type {
+	service := g.services[serviceID]
+	if !service.autowire {
+		return nil
+	}
+
+	argumentTypes := []reflect.Type{}
+	newFuncType := service.newFunc.Type()
+	for i, dependencyID := range service.dependencies {
+		if dependencyID == unresolvedDependencyID {
+			argumentTypes = append(argumentTypes, newFuncType.In(contextArgumentsCount(newFuncType)+i))
+		}
+	}
+
+	return argumentTypes
+}
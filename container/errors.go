@@ -0,0 +1,212 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+//UnknownServiceError is returned when a requested service is not declared in the container
+type UnknownServiceError struct {
+	ServiceID string
+}
+
+func (e *UnknownServiceError) Error() string {
+	return fmt.Sprintf("Unknown dependency '%s'", e.ServiceID)
+}
+
+//CycleError is returned when a service requires itself directly or through its dependencies,
+//the Path starts and ends with the same service, e.g. [a b a]
+type CycleError struct {
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("Detected dependencies' cycle: %s", strings.Join(e.Path, "->"))
+}
+
+//TypeMismatchError is returned when a service cannot be used as the requested type. If Arg is not empty, the service
+//Arg was provided as an argument to the constructor of the ServiceID service, otherwise the ServiceID service itself
+//was requested with a wrong type
+type TypeMismatchError struct {
+	ServiceID string
+	Arg       string
+	Expected  reflect.Type
+	Provided  reflect.Type
+}
+
+func (e *TypeMismatchError) Error() string {
+	if e.Arg != "" {
+		return fmt.Sprintf(
+			"Cannot use the provided dependency '%s' of type '%s' as '%s' in the Constr function call [check '%s' service]",
+			e.Arg,
+			e.Provided,
+			e.Expected,
+			e.ServiceID,
+		)
+	}
+
+	return fmt.Sprintf(
+		"Cannot use the service '%s' of type '%v' as '%v' [check '%s' service]",
+		e.ServiceID,
+		e.Provided,
+		e.Expected,
+		e.ServiceID,
+	)
+}
+
+//ConstructorError is returned when the constructor of the ServiceID service fails, the Cause is either the error
+//of the constructor or the error of one of its dependencies. The Path is the chain of services from the top level
+//request to the ServiceID service and the Source is the file and line of the constructor declaration.
+//The error text starts every failure with its resolution path, e.g.
+//api_server -> user_repo -> db: dial tcp: connection refused [check 'db' service]
+//Use the %+v verb to print the full resolution path of every failure with the constructor source, e.g.
+//api_server -> user_repo -> db: dial tcp: connection refused (at /app/db.go:12)
+type ConstructorError struct {
+	ServiceID string
+	Cause     error
+	Path      []string
+	Source    string
+}
+
+func (e *ConstructorError) Error() string {
+	return strings.Join(e.failureLines(false), ";\n")
+}
+
+//Unwrap gives the cause of the failure
+func (e *ConstructorError) Unwrap() error {
+	return e.Cause
+}
+
+//Format prints the resolution trace of every failure with the %+v verb and the error text otherwise
+func (e *ConstructorError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+//Trace gives the resolution path of every failure behind the error, one failure per line
+func (e *ConstructorError) Trace() string {
+	return strings.Join(e.failureLines(true), "\n")
+}
+
+//failureLines describes every failure behind the error, a trace line has the full path and the constructor source,
+//while an error text line has the path only if the failure happened in a dependency of the requested service
+func (e *ConstructorError) failureLines(isTrace bool) []string {
+	switch cause := e.Cause.(type) {
+	case *ConstructorError:
+		return cause.failureLines(isTrace)
+	case *MultiError:
+		lines := []string{}
+		for _, err := range cause.Errors {
+			if constructorErr, ok := err.(*ConstructorError); ok {
+				lines = append(lines, constructorErr.failureLines(isTrace)...)
+				continue
+			}
+			lines = append(lines, e.describeFailure(err, isTrace))
+		}
+		return lines
+	}
+
+	return []string{e.describeFailure(e.Cause, isTrace)}
+}
+
+func (e *ConstructorError) describeFailure(err error, isTrace bool) string {
+	if !isTrace {
+		failure := err.Error()
+		errorMsgSuffix := fmt.Sprintf(" [check '%s' service]", e.ServiceID)
+		if !strings.Contains(failure, errorMsgSuffix) {
+			failure += errorMsgSuffix
+		}
+
+		if len(e.Path) > 1 {
+			failure = fmt.Sprintf("%s: %s", strings.Join(e.Path, " -> "), failure)
+		}
+
+		return failure
+	}
+
+	path := e.Path
+	if len(path) == 0 {
+		path = []string{e.ServiceID}
+	}
+
+	failure := fmt.Sprintf("%s: %s", strings.Join(path, " -> "), err.Error())
+	if e.Source != "" {
+		failure += fmt.Sprintf(" (at %s)", e.Source)
+	}
+
+	return failure
+}
+
+//MultiError holds several errors which happened at once, e.g. failures of several constructor arguments
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	errorStrings := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errorStrings = append(errorStrings, err.Error())
+	}
+
+	return strings.Join(errorStrings, ";\n")
+}
+
+//Unwrap gives all collected errors
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+//Format prints the resolution trace of every failure with the %+v verb and the error text otherwise
+func (e *MultiError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+//Trace gives the resolution path of every failure behind the collected errors, one failure per line
+func (e *MultiError) Trace() string {
+	lines := []string{}
+	for _, err := range e.Errors {
+		switch typedErr := err.(type) {
+		case *ConstructorError:
+			lines = append(lines, typedErr.Trace())
+		case *MultiError:
+			lines = append(lines, typedErr.Trace())
+		default:
+			lines = append(lines, err.Error())
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+type traceableError interface {
+	error
+	Trace() string
+}
+
+func formatError(err traceableError, s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprint(s, err.Trace())
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", err.Error())
+	default:
+		fmt.Fprint(s, err.Error())
+	}
+}
+
+//getFuncSource gives the file and line of a function declaration by its entry point
+func getFuncSource(pc uintptr) string {
+	if pc == 0 {
+		return ""
+	}
+
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+
+	file, line := fn.FileLine(pc)
+
+	return fmt.Sprintf("%s:%d", file, line)
+}
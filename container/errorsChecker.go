@@ -1,27 +1,26 @@
 package container
 
-import (
-	coreErrors "errors"
-	"strings"
-)
-
 func panicIfError(err error) {
 	if err != nil {
 		panic(err)
 	}
 }
 
+//mergeErrors gives a single error as it is and several errors as a MultiError, nil errors are skipped
 func mergeErrors(errors []error) error {
-	if len(errors) == 0 {
-		return nil
-	}
-
-	errorStrings := []string{}
+	notNilErrors := []error{}
 	for _, err := range errors {
 		if err != nil {
-			errorStrings = append(errorStrings, err.Error())
+			notNilErrors = append(notNilErrors, err)
 		}
 	}
 
-	return coreErrors.New(strings.Join(errorStrings, ";\n"))
+	switch len(notNilErrors) {
+	case 0:
+		return nil
+	case 1:
+		return notNilErrors[0]
+	default:
+		return &MultiError{Errors: notNilErrors}
+	}
 }
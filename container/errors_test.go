@@ -0,0 +1,185 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+var errConnectionRefused = errors.New("Connection refused")
+
+func TestUnknownServiceError(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+
+	_, err := cont.GetSecure("book_storage", true)
+	assertErrorText("Unknown dependency 'db' [check 'book_storage' service]", err, t)
+
+	var unknownServiceErr *UnknownServiceError
+	if !errors.As(err, &unknownServiceErr) || unknownServiceErr.ServiceID != "db" {
+		t.Errorf("UnknownServiceError for 'db' is expected in '%v'", err)
+	}
+
+	var constructorErr *ConstructorError
+	if !errors.As(err, &constructorErr) || constructorErr.ServiceID != "book_storage" {
+		t.Errorf("ConstructorError for 'book_storage' is expected in '%v'", err)
+	}
+}
+
+func TestCycleError(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("user_name", func(roleName string) string { return roleName }, "role_name")
+	cont.AddNewMethod("role_name", func(userName string) string { return userName }, "user_name")
+
+	_, err := cont.GetSecure("user_name", true)
+
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("CycleError is expected in '%v'", err)
+	}
+
+	if !reflect.DeepEqual(cycleErr.Path, []string{"user_name", "role_name", "user_name"}) {
+		t.Errorf("Unexpected cycle path %v", cycleErr.Path)
+	}
+}
+
+func TestTypeMismatchErrorOfConstructorArgument(t *testing.T) {
+	cont := CreateContainer()
+	cont.AddNewMethod("another_finder", mocks.NewBookFinder, "book_creator", "book_storage")
+
+	_, err := cont.GetSecure("another_finder", true)
+
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) || len(multiErr.Errors) != 2 {
+		t.Fatalf("MultiError with 2 errors is expected in '%v'", err)
+	}
+
+	var typeMismatchErr *TypeMismatchError
+	if !errors.As(multiErr.Errors[1], &typeMismatchErr) {
+		t.Fatalf("TypeMismatchError is expected in '%v'", multiErr.Errors[1])
+	}
+
+	if typeMismatchErr.ServiceID != "another_finder" ||
+		typeMismatchErr.Arg != "book_storage" ||
+		typeMismatchErr.Expected != reflect.TypeOf(mocks.BookCreator{}) ||
+		typeMismatchErr.Provided != reflect.TypeOf(mocks.BookStorage{}) {
+		t.Errorf("Unexpected TypeMismatchError %+v", typeMismatchErr)
+	}
+}
+
+func TestConstructorErrorsAreReachable(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func() (*mocks.FakeDb, error) {
+		return nil, errConnectionRefused
+	})
+	cont.AddConstructor("cache", func(c Container) (interface{}, error) {
+		return nil, errors.New("Cache is not available")
+	})
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	cont.AddNewMethod("cache_manager", mocks.NewCacheManager, "cache")
+	cont.AddNewMethod(
+		"book_downloader",
+		func(bs mocks.BookStorage, cm mocks.CacheManager) bool { return true },
+		"book_storage",
+		"cache_manager",
+	)
+
+	_, err := cont.GetSecure("book_downloader", true)
+	assertErrorText(
+		"book_downloader -> book_storage -> db: Connection refused [check 'db' service];\n"+
+			"book_downloader -> cache_manager -> cache: Cache is not available [check 'cache' service]",
+		err,
+		t,
+	)
+
+	if !errors.Is(err, errConnectionRefused) {
+		t.Errorf("The error of the 'db' constructor should be reachable in '%v'", err)
+	}
+
+	var constructorErr *ConstructorError
+	if !errors.As(err, &constructorErr) || constructorErr.ServiceID != "book_downloader" {
+		t.Errorf("ConstructorError for 'book_downloader' is expected in '%v'", err)
+	}
+}
+
+func TestConstructorErrorResolutionPath(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func(connectionString string) (*mocks.FakeDb, error) {
+		return nil, errConnectionRefused
+	}, "connection_string")
+	cont.AddConstructor("connection_string", func(c Container) (interface{}, error) {
+		return "mysql://localhost", nil
+	})
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	cont.AddNewMethod("book_finder", mocks.NewBookFinder, "book_storage", "book_creator")
+	cont.AddNewMethod("book_creator", func() mocks.BookCreator { return mocks.BookCreator{} })
+
+	_, err := cont.GetSecure("book_finder", true)
+
+	var constructorErr *ConstructorError
+	if !errors.As(err, &constructorErr) {
+		t.Fatalf("ConstructorError is expected in '%v'", err)
+	}
+
+	if !reflect.DeepEqual(constructorErr.Path, []string{"book_finder"}) {
+		t.Errorf("Unexpected resolution path %v", constructorErr.Path)
+	}
+
+	dbErr := constructorErr
+	for errors.As(dbErr.Cause, &dbErr) {
+	}
+
+	if !reflect.DeepEqual(dbErr.Path, []string{"book_finder", "book_storage", "db"}) {
+		t.Errorf("Unexpected resolution path %v", dbErr.Path)
+	}
+
+	if !strings.Contains(dbErr.Source, "errors_test.go:") {
+		t.Errorf("The source of the 'db' constructor should be in errors_test.go but is '%s'", dbErr.Source)
+	}
+
+	trace := fmt.Sprintf("%+v", err)
+	expectedTrace := "book_finder -> book_storage -> db: Connection refused (at " + dbErr.Source + ")"
+	if trace != expectedTrace {
+		t.Errorf("Expected trace '%s' but got '%s'", expectedTrace, trace)
+	}
+
+	if fmt.Sprintf("%v", err) != err.Error() {
+		t.Errorf("The %%v verb should print the error text but printed '%v'", err)
+	}
+}
+
+func TestMultiErrorTrace(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	cont.AddConstructor("cache", func(c Container) (interface{}, error) {
+		return nil, errors.New("Cache is not available")
+	})
+	cont.AddNewMethod("cache_manager", mocks.NewCacheManager, "cache")
+	cont.AddNewMethod(
+		"book_downloader",
+		func(bs mocks.BookStorage, cm mocks.CacheManager) bool { return true },
+		"book_storage",
+		"cache_manager",
+	)
+
+	_, err := cont.GetSecure("book_downloader", true)
+
+	traceLines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	if len(traceLines) != 2 {
+		t.Fatalf("Expected 2 failures in the trace but got %v", traceLines)
+	}
+
+	expectedPrefixes := []string{
+		"book_downloader -> book_storage: Unknown dependency 'db' (at ",
+		"book_downloader -> cache_manager -> cache: Cache is not available (at ",
+	}
+	for i, expectedPrefix := range expectedPrefixes {
+		if !strings.HasPrefix(traceLines[i], expectedPrefix) {
+			t.Errorf("Trace line '%s' should start with '%s'", traceLines[i], expectedPrefix)
+		}
+	}
+}
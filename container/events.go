@@ -1,5 +1,7 @@
 package container
 
+import "sync"
+
 //serviceNotificationCallback is a function that receives Observer as a Service interested in a dependency
 //received in the second argument so you can call it as Observer.SetSomeDependency(dependency)
 type serviceNotificationCallback func(serviceInterestedInDependency interface{}, dependency interface{}) error
@@ -9,6 +11,7 @@ type serviceNotificationCallback func(serviceInterestedInDependency interface{},
 type EventsContainer struct {
 	dependencyEvents             map[string][]string
 	serviceNotificationCallbacks map[string]map[string]serviceNotificationCallback
+	mutex                        sync.RWMutex
 }
 
 //NewEventsContainer EventsContainer Constr
@@ -21,6 +24,9 @@ func NewEventsContainer() *EventsContainer {
 
 //registerDependencyEvent triggers an Event about adding a concrete dependency to the container
 func (ec *EventsContainer) registerDependencyEvent(eventName, dependencyName string) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+
 	ec.initEventCollection(eventName)
 	ec.dependencyEvents[eventName] = append(ec.dependencyEvents[eventName], dependencyName)
 }
@@ -31,9 +37,6 @@ func (ec *EventsContainer) addDependencyObserver(
 	serviceId string,
 	callbackToProvideDependencyToService interface{},
 ) error {
-	if ec.serviceNotificationCallbacks[serviceId] == nil {
-		ec.serviceNotificationCallbacks[serviceId] = map[string]serviceNotificationCallback{}
-	}
 	notifCallack, err := wrapCallbackToProvideDependencyToServiceIntoServiceNotificationCallback(
 		callbackToProvideDependencyToService,
 		eventName,
@@ -43,50 +46,129 @@ func (ec *EventsContainer) addDependencyObserver(
 		return err
 	}
 
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+
+	if ec.serviceNotificationCallbacks[serviceId] == nil {
+		ec.serviceNotificationCallbacks[serviceId] = map[string]serviceNotificationCallback{}
+	}
 	ec.serviceNotificationCallbacks[serviceId][eventName] = notifCallack
 	return nil
 }
 
 //collectDependencyEventsForService we call Observer methods with all the Config that it's interested in
+//onDispatched is called after every successful notification if it's provided
 func (ec *EventsContainer) collectDependencyEventsForService(
 	c Container,
 	serviceId string,
 	serviceInstance interface{},
+	onDispatched func(eventName, observerID, dependencyName string),
 ) error {
-	dependencyEventSubscribedServices, eventObserverFound := ec.serviceNotificationCallbacks[serviceId]
-	if !eventObserverFound {
-		return nil
-	}
+	notifications := ec.getNotificationsForService(serviceId)
 
 	errs := []error{}
-	for eventName, serviceNotificationCallback := range dependencyEventSubscribedServices {
+	for _, notification := range notifications {
+		dependency := c.Get(notification.dependencyName, true)
+		err := notification.callback(serviceInstance, dependency)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if onDispatched != nil {
+			onDispatched(notification.eventName, serviceId, notification.dependencyName)
+		}
+	}
+
+	return mergeErrors(errs)
+}
+
+//serviceNotification is a dependency which should be provided to the Observer with the callback
+type serviceNotification struct {
+	eventName      string
+	dependencyName string
+	callback       serviceNotificationCallback
+}
+
+//getNotificationsForService copies all notifications for the Observer, so dependencies can be created without holding the lock
+func (ec *EventsContainer) getNotificationsForService(serviceId string) []serviceNotification {
+	ec.mutex.RLock()
+	defer ec.mutex.RUnlock()
+
+	notifications := []serviceNotification{}
+	for eventName, serviceNotificationCallback := range ec.serviceNotificationCallbacks[serviceId] {
 		dependencies, eventFound := ec.dependencyEvents[eventName]
 		if !eventFound {
 			continue
 		}
 
 		for _, dependencyName := range dependencies {
-			dependency := c.Get(dependencyName, true)
-			err := serviceNotificationCallback(serviceInstance, dependency)
-			if err != nil {
-				errs = append(errs, err)
+			notifications = append(
+				notifications,
+				serviceNotification{eventName: eventName, dependencyName: dependencyName, callback: serviceNotificationCallback},
+			)
+		}
+	}
+
+	return notifications
+}
+
+//getDependencyEvents gives names of events which provide the dependency to observers
+func (ec *EventsContainer) getDependencyEvents(dependencyName string) []string {
+	ec.mutex.RLock()
+	defer ec.mutex.RUnlock()
+
+	eventNames := []string{}
+	for eventName, dependencies := range ec.dependencyEvents {
+		for _, registeredDependencyName := range dependencies {
+			if registeredDependencyName == dependencyName {
+				eventNames = append(eventNames, eventName)
+				break
 			}
 		}
 	}
 
-	return mergeErrors(errs)
+	return eventNames
+}
+
+//getObservedEvents gives names of events the Observer is interested in
+func (ec *EventsContainer) getObservedEvents(serviceId string) []string {
+	ec.mutex.RLock()
+	defer ec.mutex.RUnlock()
+
+	eventNames := []string{}
+	for eventName := range ec.serviceNotificationCallbacks[serviceId] {
+		eventNames = append(eventNames, eventName)
+	}
+
+	return eventNames
 }
 
 //merge helps to accumulate Event collections when we try to merge containers
-func (ec *EventsContainer) merge(ecToCopy EventsContainer) error {
+func (ec *EventsContainer) merge(ecToCopy *EventsContainer) error {
+	ecToCopy.mutex.RLock()
+	dependencyEvents := map[string][]string{}
 	for ecKey, events := range ecToCopy.dependencyEvents {
+		dependencyEvents[ecKey] = append([]string{}, events...)
+	}
+
+	serviceNotificationCallbacks := map[string]map[string]serviceNotificationCallback{}
+	for observerId, dependencyNotifiers := range ecToCopy.serviceNotificationCallbacks {
+		serviceNotificationCallbacks[observerId] = map[string]serviceNotificationCallback{}
+		for eventName, dependencyNotifier := range dependencyNotifiers {
+			serviceNotificationCallbacks[observerId][eventName] = dependencyNotifier
+		}
+	}
+	ecToCopy.mutex.RUnlock()
+
+	for ecKey, events := range dependencyEvents {
 		for _, dependencyName := range events {
 			ec.registerDependencyEvent(ecKey, dependencyName)
 		}
 	}
 
 	errs := []error{}
-	for observerId, dependencyNotifiers := range ecToCopy.serviceNotificationCallbacks {
+	for observerId, dependencyNotifiers := range serviceNotificationCallbacks {
 		for eventName, dependencyNotifier := range dependencyNotifiers {
 			err := ec.addDependencyObserver(eventName, observerId, dependencyNotifier)
 			if err != nil {
@@ -98,6 +180,37 @@ func (ec *EventsContainer) merge(ecToCopy EventsContainer) error {
 	return mergeErrors(errs)
 }
 
+//copy gives an independent copy of events and observers
+func (ec *EventsContainer) copy() *EventsContainer {
+	ec.mutex.RLock()
+	defer ec.mutex.RUnlock()
+
+	eventsCopy := NewEventsContainer()
+	for eventName, dependencies := range ec.dependencyEvents {
+		eventsCopy.dependencyEvents[eventName] = append([]string{}, dependencies...)
+	}
+
+	for observerId, dependencyNotifiers := range ec.serviceNotificationCallbacks {
+		eventsCopy.serviceNotificationCallbacks[observerId] = map[string]serviceNotificationCallback{}
+		for eventName, dependencyNotifier := range dependencyNotifiers {
+			eventsCopy.serviceNotificationCallbacks[observerId][eventName] = dependencyNotifier
+		}
+	}
+
+	return eventsCopy
+}
+
+//replace sets events and observers to a copy of the provided ones
+func (ec *EventsContainer) replace(source *EventsContainer) {
+	sourceCopy := source.copy()
+
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+
+	ec.dependencyEvents = sourceCopy.dependencyEvents
+	ec.serviceNotificationCallbacks = sourceCopy.serviceNotificationCallbacks
+}
+
 func (ec *EventsContainer) initEventCollection(eventName string) {
 	if ec.dependencyEvents[eventName] == nil {
 		ec.dependencyEvents[eventName] = []string{}
@@ -57,13 +57,13 @@ func TestEventMerging(t *testing.T) {
 	evCont2.addDependencyObserver("event2", "observerId2", funcToGetNotificationAboutDependency)
 	evCont2.registerDependencyEvent("event2", "dependency2")
 
-	evCont1.merge(*evCont2)
+	evCont1.merge(evCont2)
 
 	dependencyInstance := "someDependencyInstance"
 	containerMock := ContainerInterfaceMock{service: dependencyInstance}
 	serviceInstance := "someServiceInstance"
 
-	evCont1.collectDependencyEventsForService(&containerMock, "observerId2", serviceInstance)
+	evCont1.collectDependencyEventsForService(&containerMock, "observerId2", serviceInstance, nil)
 
 	if !funcToGetNotificationIsCalled {
 		t.Errorf(
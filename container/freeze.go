@@ -0,0 +1,290 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+//newMethodDeclaration keeps a New method with the ids of its dependencies, so it can be compiled by Freeze
+type newMethodDeclaration struct {
+	newMethod     reflect.Value
+	argumentNames []string
+	isAutowired   bool
+}
+
+func newNewMethodDeclaration(newMethod interface{}, argumentNames []string) *newMethodDeclaration {
+	return &newMethodDeclaration{newMethod: reflect.ValueOf(newMethod), argumentNames: argumentNames}
+}
+
+func newAutowiredNewMethodDeclaration(newMethod interface{}) *newMethodDeclaration {
+	return &newMethodDeclaration{newMethod: reflect.ValueOf(newMethod), isAutowired: true}
+}
+
+//resolutionPlan is a New method with precompiled arguments which is called without repeated type checks
+type resolutionPlan struct {
+	serviceID     string
+	newMethod     reflect.Value
+	argumentNames []string
+	arguments     []argumentPlan
+	hasContext    bool
+	errorIndex    int
+	serviceIndex  int
+	//owner is the frozen container, the plan is valid only for resolutions in it as a scope can override dependencies
+	owner    *RuntimeContainer
+	fallback NewFuncConstructor
+}
+
+//argumentPlan describes how a dependency is passed to a New method, if the declared type of the dependency is
+//compatible with the argument, it's passed without checks. A dependency declared in the frozen root container
+//is fetched with its precompiled lifetime as declarations of the container can't change anymore
+type argumentPlan struct {
+	argumentType reflect.Type
+	isChecked    bool
+	isDeclared   bool
+	lifetime     Lifetime
+	isParameter  bool
+}
+
+//Freeze checks types of all New methods declared in the container once and compiles them to resolution plans,
+//so services are created with as little reflection as possible. Declaration of new services in the frozen container
+//is rejected, scopes of the container can still declare their own services
+func (rc *RuntimeContainer) Freeze() error {
+	if rc.IsFrozen() {
+		return nil
+	}
+
+	//autowired dependencies are found before locking the container as the lookup reads it
+	autowiredArgumentNames := map[string][]string{}
+	for id, declaration := range rc.getNewMethods() {
+		if !declaration.isAutowired || rc.parent != nil {
+			continue
+		}
+
+		argumentNames, err := rc.autowireArguments(declaration.newMethod, id)
+		if err == nil {
+			autowiredArgumentNames[id] = argumentNames
+		}
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	ids := make([]string, 0, len(rc.newMethods))
+	for id := range rc.newMethods {
+		if _, isConstructor := rc.constructors[id]; !isConstructor {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	errs := []error{}
+	plans := map[string]*resolutionPlan{}
+	for _, id := range ids {
+		declaration := rc.newMethods[id]
+		argumentNames := declaration.argumentNames
+		if declaration.isAutowired {
+			var isAutowired bool
+			argumentNames, isAutowired = autowiredArgumentNames[id]
+			if !isAutowired {
+				//such services are autowired at runtime, e.g. in scopes which declare the missing dependencies
+				continue
+			}
+		}
+
+		plan, err := rc.compileResolutionPlan(id, declaration.newMethod, argumentNames)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if plan != nil {
+			plans[id] = plan
+		}
+	}
+
+	err := mergeErrors(errs)
+	if err != nil {
+		return err
+	}
+
+	for id, plan := range plans {
+		rc.newFuncConstructors[id] = plan.call
+	}
+	rc.isFrozen = true
+
+	return nil
+}
+
+//IsFrozen tells if the container was frozen
+func (rc *RuntimeContainer) IsFrozen() bool {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	return rc.isFrozen
+}
+
+//compileResolutionPlan checks types of the dependencies declared in the container, must be called under the mutex.
+//No plan is given for a variadic New method with tagged services as their count is known only at runtime
+func (rc *RuntimeContainer) compileResolutionPlan(
+	id string,
+	newMethod reflect.Value,
+	argumentNames []string,
+) (*resolutionPlan, error) {
+	newMethodType := newMethod.Type()
+	plan := &resolutionPlan{
+		serviceID:     id,
+		newMethod:     newMethod,
+		argumentNames: argumentNames,
+		arguments:     make([]argumentPlan, len(argumentNames)),
+		hasContext:    skippedContextArgumentsCount(newMethodType, len(argumentNames)) > 0,
+		errorIndex:    -1,
+		owner:         rc,
+		fallback:      rc.newFuncConstructors[id],
+	}
+
+	if newMethodType.NumOut() == 2 {
+		plan.errorIndex, plan.serviceIndex = 1, 0
+		if isErrorType(newMethodType.Out(0)) {
+			plan.errorIndex, plan.serviceIndex = 0, 1
+		}
+	}
+
+	skippedInputCount := skippedContextArgumentsCount(newMethodType, len(argumentNames))
+	errs := []error{}
+	for i, argumentName := range argumentNames {
+		argumentIndex := skippedInputCount + i
+		var argumentType reflect.Type
+		if newMethodType.IsVariadic() && argumentIndex >= newMethodType.NumIn()-1 {
+			if _, isTagReference := parseTagReference(argumentName); isTagReference {
+				return nil, nil
+			}
+			argumentType = newMethodType.In(newMethodType.NumIn() - 1).Elem()
+		} else {
+			argumentType = newMethodType.In(argumentIndex)
+		}
+		plan.arguments[i] = argumentPlan{argumentType: argumentType}
+
+		_, isConstructor := rc.constructors[argumentName]
+		_, isNewFunc := rc.newFuncConstructors[argumentName]
+		if rc.parent == nil && (isConstructor || isNewFunc) {
+			plan.arguments[i].isDeclared = true
+			plan.arguments[i].lifetime = rc.lifetimes[argumentName]
+			_, plan.arguments[i].isParameter = rc.parameters[argumentName]
+		}
+
+		dependencyType, isDeclared := rc.serviceTypes[argumentName]
+		if isConstructor || !isDeclared {
+			continue
+		}
+
+		if !dependencyType.AssignableTo(argumentType) {
+			errs = append(errs, &TypeMismatchError{
+				ServiceID: id,
+				Arg:       argumentName,
+				Expected:  argumentType,
+				Provided:  dependencyType,
+			})
+			continue
+		}
+		plan.arguments[i].isChecked = true
+	}
+
+	return plan, mergeErrors(errs)
+}
+
+//call creates the service with the precompiled arguments
+func (rp *resolutionPlan) call(c Container, isCached bool) (interface{}, error) {
+	r, ok := c.(*resolution)
+	if !ok || r.RuntimeContainer != rp.owner {
+		return rp.fallback(c, isCached)
+	}
+
+	argumentsCount := len(rp.arguments)
+	if rp.hasContext {
+		argumentsCount++
+	}
+	argumentsToCallNewMethod := make([]reflect.Value, 0, argumentsCount)
+	if rp.hasContext {
+		argumentsToCallNewMethod = append(argumentsToCallNewMethod, reflect.ValueOf(ContextOf(c)))
+	}
+
+	var dependencies []interface{}
+	var dependencyErrs []error
+	if pool := r.getWorkerPool(); pool != nil && len(rp.argumentNames) > 1 {
+		dependencies, dependencyErrs = r.getInParallel(pool, rp.argumentNames, isCached)
+	}
+
+	var errs []error
+	for i, argument := range rp.arguments {
+		var dependency interface{}
+		var err error
+		switch {
+		case dependencies != nil:
+			dependency, err = dependencies[i], dependencyErrs[i]
+		case argument.isDeclared:
+			dependency, err = r.getPlannedDependency(rp.argumentNames[i], argument.lifetime, isCached)
+		default:
+			dependency, err = r.GetSecure(rp.argumentNames[i], isCached)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if !argument.isChecked {
+			isParameter := argument.isParameter
+			if !argument.isDeclared {
+				isParameter = isParameterDependency(r, rp.argumentNames[i])
+			}
+			reflectedDependency, err := getValidFunctionArgument(
+				argument.argumentType,
+				dependency,
+				rp.argumentNames[i],
+				rp.serviceID,
+				isParameter,
+			)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			argumentsToCallNewMethod = append(argumentsToCallNewMethod, reflectedDependency)
+			continue
+		}
+
+		if dependency == nil {
+			argumentsToCallNewMethod = append(argumentsToCallNewMethod, reflect.Zero(argument.argumentType))
+			continue
+		}
+		argumentsToCallNewMethod = append(argumentsToCallNewMethod, reflect.ValueOf(dependency))
+	}
+
+	if len(errs) > 0 {
+		return nil, mergeErrors(errs)
+	}
+
+	values := rp.newMethod.Call(argumentsToCallNewMethod)
+	if rp.errorIndex < 0 {
+		return values[0].Interface(), nil
+	}
+
+	return collectErrorAndResult(values[rp.errorIndex], values[rp.serviceIndex])
+}
+
+//getPlannedDependency fetches a dependency declared in the frozen root container without lookups of its alias,
+//lifetime and owner, the root container is the owner of all its services
+func (r *resolution) getPlannedDependency(id string, lifetime Lifetime, isCached bool) (interface{}, error) {
+	if r.ctx.Err() != nil {
+		return nil, newAbortedResolutionError(r.ctx, id)
+	}
+
+	return r.getDeclared(r.RuntimeContainer, id, lifetime, isCached)
+}
+
+//assertNotFrozen rejects declaration of a service in a frozen container, must be called under the mutex
+func (rc *RuntimeContainer) assertNotFrozen(id string) error {
+	if rc.isFrozen {
+		return fmt.Errorf("Cannot declare the service '%s' as the container is frozen", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package container
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func TestFrozenContainerCreatesServices(t *testing.T) {
+	cont := CreateContainer()
+	err := cont.Freeze()
+	assertNoError(err, t)
+
+	if !cont.IsFrozen() {
+		t.Error("The container should be frozen")
+	}
+
+	err = cont.Check()
+	assertNoError(err, t)
+
+	var bookFinder mocks.BookFinder
+	cont.Scan("book_finder_declared_statically", &bookFinder)
+	book, _ := bookFinder.FindBook("one")
+	if book.Id != "One" {
+		t.Errorf("Unexpected book %+v", book)
+	}
+}
+
+func TestFrozenContainerRejectsDeclarations(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func() *mocks.FakeDb { return mocks.NewFakeDb("someConnectionString") })
+	err := cont.Freeze()
+	assertNoError(err, t)
+
+	err = cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	assertErrorText("Cannot declare the service 'book_storage' as the container is frozen", err, t)
+
+	err = cont.AddAutowiredNewMethod("book_storage", mocks.NewBookStorage)
+	assertErrorText("Cannot declare the service 'book_storage' as the container is frozen", err, t)
+
+	err = cont.AddConstructor("connection_string", func(c Container) (interface{}, error) {
+		return "someConnectionString", nil
+	})
+	assertErrorText("Cannot declare the service 'connection_string' as the container is frozen", err, t)
+
+	err = cont.Merge(NewRuntimeContainer())
+	assertErrorText("Cannot merge containers because the container is frozen", err, t)
+
+	err = cont.AddDependencyObserver("db_created", "db", func(db *mocks.FakeDb, dependency interface{}) {})
+	assertErrorText("Cannot declare the service 'db' as the container is frozen", err, t)
+
+	func() {
+		defer ExpectPanic(t, "Cannot declare the service 'db' as the container is frozen")
+		cont.SetLifetime("db", Transient)
+	}()
+
+	defer ExpectPanic(t, "Cannot declare the service 'db' as the container is frozen")
+	cont.SetConstructor("db", func(c Container) (interface{}, error) {
+		return nil, nil
+	})
+}
+
+func TestFreezeChecksTypes(t *testing.T) {
+	cont := CreateContainer()
+	cont.AddNewMethod("wrong_link_provider", mocks.NewBookLinkProvider, "static_files_url", "web_fetcher")
+
+	err := cont.Freeze()
+	assertErrorText(
+		"Cannot use the provided dependency 'web_fetcher' of type '*mocks.WebFetcher' as 'mocks.BookFinder' "+
+			"in the Constr function call [check 'wrong_link_provider' service]",
+		err,
+		t,
+	)
+
+	var typeMismatchErr *TypeMismatchError
+	if !errors.As(err, &typeMismatchErr) {
+		t.Errorf("TypeMismatchError is expected in '%v'", err)
+	}
+
+	if cont.IsFrozen() {
+		t.Error("The container should not be frozen if types are not compatible")
+	}
+}
+
+func TestFrozenContainerScopes(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func() *mocks.FakeDb { return mocks.NewFakeDb("someConnectionString") })
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	cont.SetLifetime("book_storage", Transient)
+	cont.AddAutowiredNewMethod("authors_storage", mocks.NewAuthorsStorage)
+	cont.AddNewMethod("nil_cache", func() mocks.Cache { return nil })
+	cont.AddNewMethod("cache_manager", mocks.NewCacheManager, "nil_cache")
+
+	err := cont.Freeze()
+	assertNoError(err, t)
+
+	AssertExpectedDependency(cont, "cache_manager", mocks.NewCacheManager(nil), t)
+	AssertExpectedDependency(cont, "authors_storage", mocks.NewAuthorsStorage(cont.Get("db", true).(*mocks.FakeDb)), t)
+
+	scopeDb := mocks.NewFakeDb("someConnectionString")
+	scope := cont.NewScope()
+	scope.AddConstructor("db", func(c Container) (interface{}, error) {
+		return scopeDb, nil
+	})
+
+	AssertExpectedDependency(scope, "book_storage", mocks.NewBookStorage(scopeDb), t)
+}
+
+func TestFrozenContainerRespectsLifetimesOfDependencies(t *testing.T) {
+	cont, dbCreationsCount := createContainerWithCountedDb(Transient)
+	err := cont.Freeze()
+	assertNoError(err, t)
+
+	cont.Get("book_storage", false)
+	cont.Get("book_storage", false)
+	if *dbCreationsCount != 2 {
+		t.Errorf("Transient 'db' should be created for every consumer, but it was created %d times", *dbCreationsCount)
+	}
+
+	cont = createContainerWithScopedServices()
+	cont.AddNewMethod("shared_storage", mocks.NewBookStorage, "transaction")
+	err = cont.Freeze()
+	assertNoError(err, t)
+
+	_, err = cont.NewScope().GetSecure("shared_storage", true)
+	assertErrorText(
+		"The scoped service 'transaction' cannot be a dependency of a service shared between scopes "+
+			"[check 'shared_storage' service]",
+		err,
+		t,
+	)
+}
+
+func createContainerForBenchmark(b *testing.B, isFrozen bool) *RuntimeContainer {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func() *mocks.FakeDb { return &mocks.FakeDb{} })
+	cont.AddNewMethod("book_creator", func() mocks.BookCreator { return mocks.BookCreator{} })
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	cont.AddNewMethod("book_finder", mocks.NewBookFinder, "book_storage", "book_creator")
+	cont.AddAutowiredNewMethod("autowired_book_finder", mocks.NewBookFinder)
+	cont.SetLifetime("book_storage", Transient)
+	cont.SetLifetime("book_finder", Transient)
+	cont.SetLifetime("autowired_book_finder", Transient)
+
+	if isFrozen {
+		err := cont.Freeze()
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return cont
+}
+
+func BenchmarkTransientNewMethod(b *testing.B) {
+	for _, isFrozen := range []bool{false, true} {
+		name := "dynamic"
+		if isFrozen {
+			name = "frozen"
+		}
+
+		for _, id := range []string{"book_finder", "autowired_book_finder"} {
+			b.Run(name+"/"+id, func(b *testing.B) {
+				cont := createContainerForBenchmark(b, isFrozen)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					_, err := cont.GetSecure(id, true)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
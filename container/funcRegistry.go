@@ -0,0 +1,99 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+//FuncRegistry maps names used in config files to Go functions, e.g. new funcs, constructors,
+//observer callbacks and garbage collection functions
+type FuncRegistry struct {
+	mutex sync.RWMutex
+	funcs map[string]interface{}
+}
+
+//NewFuncRegistry creates an empty functions registry
+func NewFuncRegistry() *FuncRegistry {
+	return &FuncRegistry{funcs: map[string]interface{}{}}
+}
+
+//RegisterFunc makes a function available in config files under the provided name
+func (fr *FuncRegistry) RegisterFunc(name string, function interface{}) error {
+	if name == "" {
+		return fmt.Errorf("A non empty name is expected for the function '%T'", function)
+	}
+
+	if !isFunction(reflect.ValueOf(function)) {
+		return fmt.Errorf("A function is expected but '%T' was provided for the name '%s'", function, name)
+	}
+
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+
+	fr.funcs[name] = function
+
+	return nil
+}
+
+//GetFunc gives a function registered under the provided name
+func (fr *FuncRegistry) GetFunc(name string) (interface{}, error) {
+	fr.mutex.RLock()
+	defer fr.mutex.RUnlock()
+
+	function, exists := fr.funcs[name]
+	if !exists {
+		return nil, fmt.Errorf("Unknown function '%s'", name)
+	}
+
+	return function, nil
+}
+
+//getConstructor gives a function registered under the provided name as a Constructor
+func (fr *FuncRegistry) getConstructor(name string) (Constructor, error) {
+	function, err := fr.GetFunc(name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch constructor := function.(type) {
+	case Constructor:
+		return constructor, nil
+	case func(c Container) (interface{}, error):
+		return constructor, nil
+	default:
+		return nil, fmt.Errorf("The function '%s' of type '%T' cannot be used as a constructor", name, function)
+	}
+}
+
+//getGarbageCollectorFunc gives a function registered under the provided name as a GarbageCollectorFunc
+func (fr *FuncRegistry) getGarbageCollectorFunc(name string) (GarbageCollectorFunc, error) {
+	function, err := fr.GetFunc(name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch garbageFunc := function.(type) {
+	case GarbageCollectorFunc:
+		return garbageFunc, nil
+	case func(service interface{}) error:
+		return garbageFunc, nil
+	default:
+		return nil, fmt.Errorf("The function '%s' of type '%T' cannot be used as a garbage collection function", name, function)
+	}
+}
+
+//Names gives sorted names of all registered functions
+func (fr *FuncRegistry) Names() []string {
+	fr.mutex.RLock()
+	defer fr.mutex.RUnlock()
+
+	names := make([]string, 0, len(fr.funcs))
+	for name := range fr.funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
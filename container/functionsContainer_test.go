@@ -68,6 +68,7 @@ func TestGarbageCollectionSuccess(t *testing.T) {
 		return
 	}
 
+	cont.Get("price_doubler", true)
 	err := cont.CollectGarbage()
 	if err != nil {
 		t.Errorf("Unexpected error %v during the garbage collection", err)
@@ -95,6 +96,7 @@ func TestDuplicatedGarbageCollectFuncs(t *testing.T) {
 	cont.AddGarbageCollectFunc("in_memory_cache", garbageCollector1)
 	cont.AddGarbageCollectFunc("in_memory_cache", garbageCollector2)
 
+	cont.Get("in_memory_cache", true)
 	err := cont.CollectGarbage()
 	if err != nil {
 		t.Errorf("Unexpected error %v during the garbage collection", err)
@@ -161,6 +163,7 @@ func TestGarbageCollectionCallOrder(t *testing.T) {
 	for _, gcServiceName := range garbageCollectionServices {
 		gcFunc := gcFuncBuilder(gcServiceName)
 		cont.AddGarbageCollectFunc(gcServiceName, gcFunc)
+		cont.Get(gcServiceName, true)
 	}
 
 	err := cont.CollectGarbage()
@@ -202,6 +205,9 @@ func TestGarbageCollectionFailures(t *testing.T) {
 	}
 	cont.AddGarbageCollectFunc("price_finder", garbageCollector3)
 
+	for _, id := range []string{"book_prices", "books", "price_finder"} {
+		cont.Get(id, true)
+	}
 	err := cont.CollectGarbage()
 
 	expectedError := "Garbage collection errors: Error 1, Error 2"
@@ -212,6 +218,23 @@ func TestGarbageCollectionFailures(t *testing.T) {
 	t.Errorf("Garbage collect function should return '%s' but '%v' is returned", expectedError, err)
 }
 
+func TestGarbageCollectionSkipsNotCreatedServices(t *testing.T) {
+	cont := PrepareContainer()
+
+	wasCalled := false
+	cont.AddGarbageCollectFunc("price_doubler", func(service interface{}) error {
+		wasCalled = true
+		return nil
+	})
+
+	err := cont.CollectGarbage()
+	assertNoError(err, t)
+	if wasCalled {
+		t.Error("Garbage collect function of a not created service should not be called")
+	}
+	assertIDs("", cont.(Introspector).Instantiated(), t)
+}
+
 func TestGarbageCollectionForUnknownService(t *testing.T) {
 	cont := PrepareContainer()
 	garbageCollector := func(service interface{}) error {
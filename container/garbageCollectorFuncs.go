@@ -1,5 +1,7 @@
 package container
 
+import "sync"
+
 type namedGarbageCollectorFunc struct {
 	f    GarbageCollectorFunc
 	name string
@@ -9,6 +11,7 @@ type namedGarbageCollectorFunc struct {
 type GarbageCollectorFuncs struct {
 	garbageCollectors []namedGarbageCollectorFunc
 	namedMap          map[string]bool
+	mutex             sync.RWMutex
 }
 
 //NewGarbageCollectorFuncs constructor
@@ -21,6 +24,9 @@ func NewGarbageCollectorFuncs() *GarbageCollectorFuncs {
 
 //Add a new garbage collector func
 func (gcf *GarbageCollectorFuncs) Add(name string, gcFunc GarbageCollectorFunc) {
+	gcf.mutex.Lock()
+	defer gcf.mutex.Unlock()
+
 	if _, exists := gcf.namedMap[name]; exists {
 		return
 	}
@@ -34,12 +40,49 @@ func (gcf *GarbageCollectorFuncs) Add(name string, gcFunc GarbageCollectorFunc)
 	gcf.namedMap[name] = true
 }
 
+//has tells if a garbage collector func is registered for the service
+func (gcf *GarbageCollectorFuncs) has(name string) bool {
+	gcf.mutex.RLock()
+	defer gcf.mutex.RUnlock()
+
+	return gcf.namedMap[name]
+}
+
 //Range iterates over garbage collectors
 func (gcf *GarbageCollectorFuncs) Range(iterFunc func(gcName string, f GarbageCollectorFunc) bool) {
-	for _, namedGcFunc := range gcf.garbageCollectors {
+	gcf.mutex.RLock()
+	garbageCollectors := append([]namedGarbageCollectorFunc{}, gcf.garbageCollectors...)
+	gcf.mutex.RUnlock()
+
+	for _, namedGcFunc := range garbageCollectors {
 		result := iterFunc(namedGcFunc.name, namedGcFunc.f)
 		if !result {
 			break
 		}
 	}
 }
+
+//copy gives an independent copy of garbage collector funcs
+func (gcf *GarbageCollectorFuncs) copy() *GarbageCollectorFuncs {
+	gcf.mutex.RLock()
+	defer gcf.mutex.RUnlock()
+
+	gcfCopy := NewGarbageCollectorFuncs()
+	gcfCopy.garbageCollectors = append(gcfCopy.garbageCollectors, gcf.garbageCollectors...)
+	for name := range gcf.namedMap {
+		gcfCopy.namedMap[name] = true
+	}
+
+	return gcfCopy
+}
+
+//replace sets garbage collector funcs to a copy of the provided ones
+func (gcf *GarbageCollectorFuncs) replace(source *GarbageCollectorFuncs) {
+	sourceCopy := source.copy()
+
+	gcf.mutex.Lock()
+	defer gcf.mutex.Unlock()
+
+	gcf.garbageCollectors = sourceCopy.garbageCollectors
+	gcf.namedMap = sourceCopy.namedMap
+}
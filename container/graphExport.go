@@ -0,0 +1,229 @@
+package container
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//GraphFormat is an output format of the ExportGraph function
+type GraphFormat string
+
+const (
+	//DotFormat exports a graph in the Graphviz DOT language
+	DotFormat GraphFormat = "dot"
+	//MermaidFormat exports a graph as a Mermaid flowchart
+	MermaidFormat GraphFormat = "mermaid"
+)
+
+const (
+	dependencyEdgeKind = "dependency"
+	eventEdgeKind      = "event"
+	unknownServiceKind = "unknown"
+)
+
+//graphEdge is a relation between a service and another service it uses, event edges go from an observer
+//to a service registered for its event
+type graphEdge struct {
+	from     string
+	to       string
+	kind     string
+	label    string
+	isCyclic bool
+}
+
+type graphNode struct {
+	id       string
+	kind     string
+	isCyclic bool
+}
+
+//exportedGraph is a format independent view of a dependency graph
+type exportedGraph struct {
+	nodes []graphNode
+	edges []graphEdge
+}
+
+//ExportGraph gives the services of a config tree and their relations in the requested format without creating any
+//service. Dependencies are drawn with solid edges, subscriptions of observers to events with dashed edges labeled with
+//the event name, services and edges forming dependency cycles are highlighted in red. Arguments of autowired services
+//without a matching service are drawn as unknown nodes named after the argument type
+func ExportGraph(tree Tree, format GraphFormat) (string, error) {
+	if format != DotFormat && format != MermaidFormat {
+		return "", fmt.Errorf("Unknown graph format '%s', supported formats are '%s' and '%s'", format, DotFormat, MermaidFormat)
+	}
+
+	err := ValidateConfigSecure(tree)
+	if err != nil {
+		return "", err
+	}
+
+	//autowiring errors are not fatal, unresolved arguments are drawn as unknown nodes
+	graph, _ := newDependencyGraph(tree)
+	exported := newExportedGraph(graph)
+	if format == DotFormat {
+		return exported.toDot(), nil
+	}
+
+	return exported.toMermaid(), nil
+}
+
+func newExportedGraph(graph *dependencyGraph) exportedGraph {
+	cyclicEdges := map[string]bool{}
+	for _, cycle := range graph.findCycles() {
+		for i := 0; i < len(cycle)-1; i++ {
+			cyclicEdges[cycle[i]+"->"+cycle[i+1]] = true
+		}
+	}
+
+	cyclicNodes := map[string]bool{}
+	for edge := range cyclicEdges {
+		cyclicNodes[strings.SplitN(edge, "->", 2)[0]] = true
+	}
+
+	exported := exportedGraph{}
+	knownNodes := map[string]bool{}
+	addNode := func(id, kind string) {
+		if knownNodes[id] {
+			return
+		}
+		knownNodes[id] = true
+		exported.nodes = append(exported.nodes, graphNode{id: id, kind: kind, isCyclic: cyclicNodes[id]})
+	}
+
+	for _, serviceID := range graph.serviceIDs {
+		addNode(serviceID, graph.services[serviceID].kind)
+	}
+
+	for _, serviceID := range graph.serviceIDs {
+		for _, dependencyID := range graph.getDependencies(serviceID) {
+			if _, exists := graph.services[dependencyID]; !exists {
+				addNode(dependencyID, unknownServiceKind)
+			}
+
+			exported.edges = append(exported.edges, graphEdge{
+				from:     serviceID,
+				to:       dependencyID,
+				kind:     dependencyEdgeKind,
+				isCyclic: cyclicEdges[serviceID+"->"+dependencyID],
+			})
+		}
+
+		for _, argumentType := range graph.getUnresolvedArguments(serviceID) {
+			addNode(argumentType.String(), unknownServiceKind)
+			exported.edges = append(exported.edges, graphEdge{
+				from: serviceID,
+				to:   argumentType.String(),
+				kind: dependencyEdgeKind,
+			})
+		}
+	}
+
+	for _, observer := range graph.observers {
+		for _, event := range graph.events {
+			if event.Name != observer.Event {
+				continue
+			}
+
+			addNode(observer.Name, unknownServiceKind)
+			addNode(event.Service, unknownServiceKind)
+			exported.edges = append(exported.edges, graphEdge{
+				from:  observer.Name,
+				to:    event.Service,
+				kind:  eventEdgeKind,
+				label: event.Name,
+			})
+		}
+	}
+
+	return exported
+}
+
+func (g exportedGraph) toDot() string {
+	dotShapes := map[string]string{
+		constructorServiceKind: "box",
+		newFuncServiceKind:     "box",
+		parameterServiceKind:   "ellipse",
+		aliasServiceKind:       "box, style=rounded",
+		unknownServiceKind:     "box, style=dashed",
+	}
+
+	lines := []string{"digraph container {"}
+	for _, node := range g.nodes {
+		attributes := "shape=" + dotShapes[node.kind]
+		if node.isCyclic {
+			attributes += ", color=red"
+		}
+		lines = append(lines, fmt.Sprintf("    %s [%s];", strconv.Quote(node.id), attributes))
+	}
+
+	for _, edge := range g.edges {
+		attributes := []string{}
+		if edge.kind == eventEdgeKind {
+			attributes = append(attributes, "style=dashed", "label="+strconv.Quote(edge.label))
+		}
+		if edge.isCyclic {
+			attributes = append(attributes, "color=red")
+		}
+
+		line := fmt.Sprintf("    %s -> %s", strconv.Quote(edge.from), strconv.Quote(edge.to))
+		if len(attributes) > 0 {
+			line += " [" + strings.Join(attributes, ", ") + "]"
+		}
+		lines = append(lines, line+";")
+	}
+	lines = append(lines, "}")
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (g exportedGraph) toMermaid() string {
+	mermaidShapes := map[string][2]string{
+		constructorServiceKind: {"[", "]"},
+		newFuncServiceKind:     {"[", "]"},
+		parameterServiceKind:   {"([", "])"},
+		aliasServiceKind:       {"(", ")"},
+		unknownServiceKind:     {"[/", "/]"},
+	}
+
+	//service ids might contain symbols which are not allowed in Mermaid ids, so nodes are numbered
+	mermaidIDs := map[string]string{}
+	cyclicNodeIDs := []string{}
+	lines := []string{"flowchart LR"}
+	for i, node := range g.nodes {
+		mermaidID := fmt.Sprintf("n%d", i)
+		mermaidIDs[node.id] = mermaidID
+		shape := mermaidShapes[node.kind]
+		lines = append(lines, fmt.Sprintf("    %s%s%s%s", mermaidID, shape[0], escapeMermaidLabel(node.id), shape[1]))
+		if node.isCyclic {
+			cyclicNodeIDs = append(cyclicNodeIDs, mermaidID)
+		}
+	}
+
+	cyclicEdgeIndexes := []string{}
+	for i, edge := range g.edges {
+		arrow := "-->"
+		if edge.kind == eventEdgeKind {
+			arrow = "-.->|" + escapeMermaidLabel(edge.label) + "|"
+		}
+		lines = append(lines, fmt.Sprintf("    %s %s %s", mermaidIDs[edge.from], arrow, mermaidIDs[edge.to]))
+		if edge.isCyclic {
+			cyclicEdgeIndexes = append(cyclicEdgeIndexes, strconv.Itoa(i))
+		}
+	}
+
+	if len(cyclicNodeIDs) > 0 {
+		lines = append(
+			lines,
+			"    classDef cycle stroke:#f00,color:#f00",
+			"    class "+strings.Join(cyclicNodeIDs, ",")+" cycle",
+			"    linkStyle "+strings.Join(cyclicEdgeIndexes, ",")+" stroke:#f00",
+		)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func escapeMermaidLabel(label string) string {
+	return `"` + strings.ReplaceAll(label, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,136 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+var exportedTree = Tree{
+	Node{Parameters: map[string]interface{}{"connection_string": "someConnectionString"}},
+	Node{ID: "db", NewFunc: mocks.NewFakeDb, ServiceNames: Services{"connection_string"}},
+	Node{ID: "book_storage", NewFunc: mocks.NewBookStorage, ServiceNames: Services{"db"}},
+	Node{ID: "statistics_gateway", NewFunc: mocks.NewStatisticsGateway},
+	Node{Ev: Event{Name: "add_stats_provider", Service: "book_storage"}},
+	Node{
+		Ob: Observer{
+			Event: "add_stats_provider",
+			Name:  "statistics_gateway",
+			Callback: func(sg *mocks.StatisticsGateway, sp mocks.StatisticsProvider) {
+				sg.AddStatisticsProvider(sp)
+			},
+		},
+	},
+	Node{ID: "user_name", NewFunc: func(roleName string) string { return roleName }, ServiceNames: Services{"role_name"}},
+	Node{ID: "role_name", NewFunc: func(userName string) string { return userName }, ServiceNames: Services{"user_name"}},
+	Node{ID: "logger", Constr: func(c Container) (interface{}, error) { return nil, nil }},
+}
+
+func TestExportGraphToDot(t *testing.T) {
+	graph, err := ExportGraph(exportedTree, DotFormat)
+	assertNoError(err, t)
+
+	expectedGraph := `digraph container {
+    "connection_string" [shape=ellipse];
+    "db" [shape=box];
+    "book_storage" [shape=box];
+    "statistics_gateway" [shape=box];
+    "user_name" [shape=box, color=red];
+    "role_name" [shape=box, color=red];
+    "logger" [shape=box];
+    "db" -> "connection_string";
+    "book_storage" -> "db";
+    "user_name" -> "role_name" [color=red];
+    "role_name" -> "user_name" [color=red];
+    "statistics_gateway" -> "book_storage" [style=dashed, label="add_stats_provider"];
+}
+`
+	if graph != expectedGraph {
+		t.Errorf("Unexpected DOT graph:\n%s\nexpected:\n%s", graph, expectedGraph)
+	}
+}
+
+func TestExportGraphToMermaid(t *testing.T) {
+	graph, err := ExportGraph(exportedTree, MermaidFormat)
+	assertNoError(err, t)
+
+	expectedGraph := `flowchart LR
+    n0(["connection_string"])
+    n1["db"]
+    n2["book_storage"]
+    n3["statistics_gateway"]
+    n4["user_name"]
+    n5["role_name"]
+    n6["logger"]
+    n1 --> n0
+    n2 --> n1
+    n4 --> n5
+    n5 --> n4
+    n3 -.->|"add_stats_provider"| n2
+    classDef cycle stroke:#f00,color:#f00
+    class n4,n5 cycle
+    linkStyle 2,3 stroke:#f00
+`
+	if graph != expectedGraph {
+		t.Errorf("Unexpected Mermaid graph:\n%s\nexpected:\n%s", graph, expectedGraph)
+	}
+}
+
+func TestExportGraphWithUnknownDependencies(t *testing.T) {
+	tree := Tree{
+		Node{ID: "db", NewFunc: mocks.NewFakeDb, ServiceNames: Services{"connection_string"}},
+	}
+
+	graph, err := ExportGraph(tree, DotFormat)
+	assertNoError(err, t)
+
+	expectedGraph := `digraph container {
+    "db" [shape=box];
+    "connection_string" [shape=box, style=dashed];
+    "db" -> "connection_string";
+}
+`
+	if graph != expectedGraph {
+		t.Errorf("Unexpected DOT graph:\n%s\nexpected:\n%s", graph, expectedGraph)
+	}
+}
+
+func TestExportGraphWithUnresolvedAutowiredArguments(t *testing.T) {
+	tree := Tree{
+		Node{ID: "in_memory_cache", NewFunc: mocks.NewInMemoryCache},
+		Node{
+			ID: "cache_manager",
+			NewFunc: func(db *mocks.FakeDb, cache mocks.Cache) mocks.CacheManager {
+				return mocks.NewCacheManager(cache)
+			},
+			Autowire: true,
+		},
+	}
+
+	graph, err := ExportGraph(tree, DotFormat)
+	assertNoError(err, t)
+
+	expectedGraph := `digraph container {
+    "in_memory_cache" [shape=box];
+    "cache_manager" [shape=box];
+    "*mocks.FakeDb" [shape=box, style=dashed];
+    "cache_manager" -> "in_memory_cache";
+    "cache_manager" -> "*mocks.FakeDb";
+}
+`
+	if graph != expectedGraph {
+		t.Errorf("Unexpected DOT graph:\n%s\nexpected:\n%s", graph, expectedGraph)
+	}
+}
+
+func TestExportGraphErrors(t *testing.T) {
+	_, err := ExportGraph(exportedTree, GraphFormat("svg"))
+	assertErrorText("Unknown graph format 'svg', supported formats are 'dot' and 'mermaid'", err, t)
+
+	_, err = ExportGraph(Tree{Node{ID: "db", NewFunc: mocks.NewFakeDb}}, DotFormat)
+	assertErrorText(
+		"The function requires 1 arguments, but 0 arguments are provided [check 'Node: {ID: db; ServiceNames: []; Event: {Name: ; Service: ;}; Observer: {Name: ; Event: ;}}' service]",
+		err,
+		t,
+	)
+}
@@ -0,0 +1,254 @@
+package container
+
+import "reflect"
+
+//ValidateGraph validates a tree of config options with relations between services and panics if something is wrong
+func ValidateGraph(tree Tree) {
+	err := ValidateGraphSecure(tree)
+
+	panicIfError(err)
+}
+
+//ValidateGraphSecure validates a tree of config options with relations between services and returns error if something
+//is wrong. Unlike the Check function of the container it doesn't create any service, it relies only on declared
+//types of new funcs and parameters. It reports unknown dependencies, incompatible dependency types, wrong arguments
+//count of variadic new funcs and dependency cycles. Services declared with constructors have no declared type,
+//so they're not validated against the arguments they're used for.
+func ValidateGraphSecure(tree Tree) error {
+	err := ValidateConfigSecure(tree)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	graph, err := newDependencyGraph(tree)
+	addErrorToCollection(&errs, err)
+
+	for _, serviceID := range graph.serviceIDs {
+		service := graph.services[serviceID]
+		if service.kind == newFuncServiceKind {
+			validateNewFuncDependencies(service, graph, &errs)
+		}
+
+		if service.kind == aliasServiceKind {
+			validateAliasDependencies(service, graph, &errs)
+		}
+	}
+
+	for _, decorator := range graph.decorators {
+		validateDecoratorDependencies(decorator, graph, &errs)
+	}
+
+	for _, observer := range graph.observers {
+		validateObserverDependencies(observer, graph, &errs)
+	}
+
+	for _, cycle := range graph.findCycles() {
+		errs = append(errs, &CycleError{Path: cycle})
+	}
+
+	return mergeErrors(errs)
+}
+
+func validateNewFuncDependencies(service *graphService, graph *dependencyGraph, errCollection *[]error) {
+	newFuncType := service.newFunc.Type()
+	skippedArgumentsCount := skippedContextArgumentsCount(newFuncType, len(service.dependencies))
+	argumentsCount := newFuncType.NumIn() - skippedArgumentsCount
+	if newFuncType.IsVariadic() && len(service.dependencies) < argumentsCount-1 {
+		registerNewErrorInCollection(
+			errCollection,
+			"The function requires at least %d arguments, but %d arguments are provided [check '%s' service]",
+			argumentsCount-1,
+			len(service.dependencies),
+			service.id,
+		)
+		return
+	}
+
+	for i, dependencyID := range service.dependencies {
+		var argumentType reflect.Type
+		isVariadicArgument := newFuncType.IsVariadic() && i >= argumentsCount-1
+		if isVariadicArgument {
+			argumentType = newFuncType.In(skippedArgumentsCount + argumentsCount - 1).Elem()
+		} else {
+			argumentType = newFuncType.In(skippedArgumentsCount + i)
+		}
+
+		if dependencyID == unresolvedDependencyID {
+			//the autowiring error is already reported
+			continue
+		}
+
+		if tag, isTagReference := parseTagReference(dependencyID); isTagReference {
+			validateTaggedDependencies(argumentType, isVariadicArgument, dependencyID, graph.tags[tag], graph, service.id, errCollection)
+			continue
+		}
+
+		if optional, isOptional := parseOptionalDependency(dependencyID); isOptional {
+			validateOptionalDependency(argumentType, optional, dependencyID, graph, service.id, errCollection)
+			continue
+		}
+
+		dependency, exists := graph.services[dependencyID]
+		if !exists {
+			*errCollection = append(*errCollection, &ConstructorError{
+				ServiceID: service.id,
+				Cause:     &UnknownServiceError{ServiceID: dependencyID},
+			})
+			continue
+		}
+
+		validateDeclaredTypes(argumentType, dependency, service.id, graph, errCollection)
+	}
+}
+
+//validateOptionalDependency checks the type of the optional dependency if it's declared or converts its default value
+func validateOptionalDependency(
+	argumentType reflect.Type,
+	optional optionalDependency,
+	dependencyName string,
+	graph *dependencyGraph,
+	serviceID string,
+	errCollection *[]error,
+) {
+	dependency, exists := graph.services[optional.id]
+	if exists {
+		validateDeclaredTypes(argumentType, dependency, serviceID, graph, errCollection)
+		return
+	}
+
+	if optional.hasDefault {
+		_, err := getValidDependencyArgument(argumentType, optional.defaultValue, dependencyName, serviceID, true)
+		addErrorToCollection(errCollection, err)
+	}
+}
+
+func validateAliasDependencies(alias *graphService, graph *dependencyGraph, errCollection *[]error) {
+	target, exists := graph.services[alias.dependencies[0]]
+	if !exists {
+		*errCollection = append(*errCollection, &ConstructorError{
+			ServiceID: alias.id,
+			Cause:     &UnknownServiceError{ServiceID: alias.dependencies[0]},
+		})
+		return
+	}
+
+	if alias.serviceType != nil {
+		validateDeclaredTypes(alias.serviceType, target, alias.id, graph, errCollection)
+	}
+}
+
+//validateTaggedDependencies checks that every tagged service can be used as an element of the slice argument or
+//as the variadic argument
+func validateTaggedDependencies(
+	argumentType reflect.Type,
+	isVariadicArgument bool,
+	tagReference string,
+	taggedServiceIDs []string,
+	graph *dependencyGraph,
+	serviceID string,
+	errCollection *[]error,
+) {
+	if !isVariadicArgument {
+		taggedServicesType := reflect.TypeOf([]interface{}{})
+		if taggedServicesType.AssignableTo(argumentType) {
+			return
+		}
+
+		if argumentType.Kind() != reflect.Slice {
+			*errCollection = append(*errCollection, &TypeMismatchError{
+				ServiceID: serviceID,
+				Arg:       tagReference,
+				Expected:  argumentType,
+				Provided:  taggedServicesType,
+			})
+			return
+		}
+		argumentType = argumentType.Elem()
+	}
+
+	for _, taggedServiceID := range taggedServiceIDs {
+		validateDeclaredTypes(argumentType, graph.services[taggedServiceID], serviceID, graph, errCollection)
+	}
+}
+
+func validateDecoratorDependencies(decorator graphDecorator, graph *dependencyGraph, errCollection *[]error) {
+	service, exists := graph.services[decorator.serviceID]
+	if !exists {
+		registerNewErrorInCollection(
+			errCollection,
+			"Cannot decorate the service '%s' as it is not declared in the container",
+			decorator.serviceID,
+		)
+		return
+	}
+
+	if service.kind == newFuncServiceKind {
+		addErrorToCollection(errCollection, decorator.decorator.assertDecorates(service.serviceType, service.id))
+	}
+
+	validateNewFuncDependencies(decorator.bound, graph, errCollection)
+}
+
+func validateObserverDependencies(observer Observer, graph *dependencyGraph, errCollection *[]error) {
+	observerService, exists := graph.services[observer.Name]
+	if !exists {
+		registerNewErrorInCollection(
+			errCollection,
+			"Unknown observer service '%s' for the event '%s'",
+			observer.Name,
+			observer.Event,
+		)
+		return
+	}
+
+	callbackType := reflect.TypeOf(observer.Callback)
+	validateDeclaredTypes(callbackType.In(0), observerService, observer.Name, graph, errCollection)
+
+	for _, event := range graph.events {
+		if event.Name != observer.Event {
+			continue
+		}
+
+		if eventService, exists := graph.services[event.Service]; exists {
+			validateDeclaredTypes(callbackType.In(1), eventService, observer.Name, graph, errCollection)
+		}
+	}
+}
+
+//validateDeclaredTypes reports dependencies which can never be used as the expected argument, a dependency declared
+//with an interface type is accepted if any implementation of it might fit the argument, a string parameter is accepted
+//if it might be converted to the argument
+func validateDeclaredTypes(
+	argumentType reflect.Type,
+	dependency *graphService,
+	serviceID string,
+	graph *dependencyGraph,
+	errCollection *[]error,
+) {
+	dependencyID := dependency.id
+	dependency = graph.resolveAlias(dependency)
+	if dependency == nil {
+		return
+	}
+
+	providedType := dependency.serviceType
+	if providedType == nil || providedType.AssignableTo(argumentType) {
+		return
+	}
+
+	if providedType.Kind() == reflect.Interface && (argumentType.Kind() == reflect.Interface || argumentType.Implements(providedType)) {
+		return
+	}
+
+	if dependency.kind == parameterServiceKind && providedType.Kind() == reflect.String && isConvertibleFromString(argumentType) {
+		return
+	}
+
+	*errCollection = append(*errCollection, &TypeMismatchError{
+		ServiceID: serviceID,
+		Arg:       dependencyID,
+		Expected:  argumentType,
+		Provided:  providedType,
+	})
+}
@@ -0,0 +1,198 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func TestValidGraph(t *testing.T) {
+	err := ValidateGraphSecure(getMockedConfigTree())
+	assertNoError(err, t)
+}
+
+func TestGraphValidationDoesNotCreateServices(t *testing.T) {
+	tree := Tree{
+		Node{
+			ID: "connection_string",
+			Constr: func(c Container) (interface{}, error) {
+				t.Error("Static validation should not create services")
+				return "someConnectionString", nil
+			},
+		},
+		Node{ID: "db", NewFunc: mocks.NewFakeDb, ServiceNames: Services{"connection_string"}},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertNoError(err, t)
+}
+
+func TestGraphValidationFailsForInvalidConfig(t *testing.T) {
+	tree := Tree{
+		Node{ID: "db", NewFunc: mocks.NewFakeDb},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertErrorText(
+		"The function requires 1 arguments, but 0 arguments are provided [check 'Node: {ID: db; ServiceNames: []; Event: {Name: ; Service: ;}; Observer: {Name: ; Event: ;}}' service]",
+		err,
+		t,
+	)
+}
+
+func TestGraphValidationOfUnknownDependencies(t *testing.T) {
+	tree := Tree{
+		Node{ID: "db", NewFunc: mocks.NewFakeDb, ServiceNames: Services{"connection_string"}},
+		Node{ID: "book_storage", NewFunc: mocks.NewBookStorage, ServiceNames: Services{"db"}},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertErrorText("Unknown dependency 'connection_string' [check 'db' service]", err, t)
+}
+
+func TestGraphValidationOfTypeMismatches(t *testing.T) {
+	tree := Tree{
+		Node{
+			Parameters: map[string]interface{}{
+				"connection_string": 123,
+				"domain":            "http://example.me",
+			},
+		},
+		Node{ID: "db", NewFunc: mocks.NewFakeDb, ServiceNames: Services{"connection_string"}},
+		Node{ID: "book_creator", NewFunc: func() mocks.BookCreator { return mocks.BookCreator{} }},
+		Node{ID: "book_storage", NewFunc: mocks.NewBookStorage, ServiceNames: Services{"db"}},
+		Node{ID: "book_finder", NewFunc: mocks.NewBookFinder, ServiceNames: Services{"book_creator", "book_storage"}},
+		Node{ID: "url_provider", NewFunc: mocks.NewUrlProviderWithDomain, ServiceNames: Services{"domain", "domain", "db"}},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertErrorText(
+		"Cannot use the provided dependency 'connection_string' of type 'int' as 'string' in the Constr function call [check 'db' service];\n"+
+			"Cannot use the provided dependency 'book_creator' of type 'mocks.BookCreator' as 'mocks.BookStorage' in the Constr function call [check 'book_finder' service];\n"+
+			"Cannot use the provided dependency 'book_storage' of type 'mocks.BookStorage' as 'mocks.BookCreator' in the Constr function call [check 'book_finder' service];\n"+
+			"Cannot use the provided dependency 'db' of type '*mocks.FakeDb' as 'string' in the Constr function call [check 'url_provider' service]",
+		err,
+		t,
+	)
+}
+
+func TestGraphValidationOfInterfaces(t *testing.T) {
+	tree := Tree{
+		Node{ID: "cache", NewFunc: func() mocks.Cache { return mocks.NewInMemoryCache() }},
+		Node{ID: "in_memory_cache", NewFunc: func(cache *mocks.InMemoryCache) bool { return true }, ServiceNames: Services{"cache"}},
+		Node{ID: "cache_manager", NewFunc: mocks.NewCacheManager, ServiceNames: Services{"cache"}},
+		Node{ID: "wrong_cache_user", NewFunc: func(cache mocks.Book) bool { return true }, ServiceNames: Services{"cache"}},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertErrorText(
+		"Cannot use the provided dependency 'cache' of type 'mocks.Cache' as 'mocks.Book' in the Constr function call [check 'wrong_cache_user' service]",
+		err,
+		t,
+	)
+}
+
+func TestGraphValidationOfVariadicArguments(t *testing.T) {
+	tree := Tree{
+		Node{ID: "url_provider", NewFunc: mocks.NewUrlProviderWithDomain},
+		Node{ID: "another_url_provider", NewFunc: mocks.NewUrlProvider},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertErrorText("The function requires at least 1 arguments, but 0 arguments are provided [check 'url_provider' service]", err, t)
+}
+
+var typedCycleTree = Tree{
+	Node{ID: "user_name", NewFunc: func(roleName string) string { return roleName }, ServiceNames: Services{"role_name"}},
+	Node{ID: "role_name", NewFunc: func(rightName string) string { return rightName }, ServiceNames: Services{"right_name"}},
+	Node{ID: "right_name", NewFunc: func(userName string) string { return userName }, ServiceNames: Services{"user_name"}},
+	Node{ID: "self_reference", NewFunc: func(s string) string { return s }, ServiceNames: Services{"self_reference"}},
+	Node{ID: "book_storage", NewFunc: mocks.NewBookStorage, ServiceNames: Services{"book_storage"}},
+}
+
+func TestGraphValidationOfCycles(t *testing.T) {
+	err := ValidateGraphSecure(typedCycleTree)
+	assertErrorText(
+		"Cannot use the provided dependency 'book_storage' of type 'mocks.BookStorage' as '*mocks.FakeDb' in the Constr function call [check 'book_storage' service];\n"+
+			"Detected dependencies' cycle: user_name->role_name->right_name->user_name;\n"+
+			"Detected dependencies' cycle: self_reference->self_reference;\n"+
+			"Detected dependencies' cycle: book_storage->book_storage",
+		err,
+		t,
+	)
+}
+
+func TestGraphValidationOfAutowiring(t *testing.T) {
+	tree := Tree{
+		Node{ID: "in_memory_cache", NewFunc: mocks.NewInMemoryCache},
+		Node{ID: "another_cache", NewFunc: mocks.NewInMemoryCache},
+		Node{ID: "cache_manager", NewFunc: mocks.NewCacheManager, Autowire: true},
+		Node{ID: "book_storage", NewFunc: mocks.NewBookStorage, Autowire: true},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertErrorText(
+		"Several services of type 'mocks.Cache' are declared for the argument 1 of the Constr function: another_cache, in_memory_cache [check 'cache_manager' service];\n"+
+			"No service of type '*mocks.FakeDb' is declared for the argument 1 of the Constr function [check 'book_storage' service]",
+		err,
+		t,
+	)
+}
+
+func TestGraphValidationOfPartlyAutowiredArguments(t *testing.T) {
+	tree := Tree{
+		Node{ID: "in_memory_cache", NewFunc: mocks.NewInMemoryCache},
+		Node{
+			ID: "cache_manager",
+			NewFunc: func(db *mocks.FakeDb, cache mocks.Cache) mocks.CacheManager {
+				return mocks.NewCacheManager(cache)
+			},
+			Autowire: true,
+		},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertErrorText(
+		"No service of type '*mocks.FakeDb' is declared for the argument 1 of the Constr function [check 'cache_manager' service]",
+		err,
+		t,
+	)
+}
+
+func TestGraphValidationOfObservers(t *testing.T) {
+	tree := Tree{
+		Node{ID: "statistics_gateway", NewFunc: mocks.NewStatisticsGateway},
+		Node{ID: "book_shelve", NewFunc: mocks.NewBookShelve},
+		Node{Ev: Event{Name: "add_stats_provider", Service: "book_shelve"}},
+		Node{
+			Ob: Observer{
+				Event: "add_stats_provider",
+				Name:  "statistics_gateway",
+				Callback: func(sg *mocks.StatisticsGateway, sp mocks.StatisticsProvider) {
+					sg.AddStatisticsProvider(sp)
+				},
+			},
+		},
+		Node{
+			Ob: Observer{
+				Event:    "add_stats_provider",
+				Name:     "unknown_gateway",
+				Callback: func(sg *mocks.StatisticsGateway, sp mocks.StatisticsProvider) {},
+			},
+		},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertErrorText(
+		"Cannot use the provided dependency 'book_shelve' of type '*mocks.BookShelve' as 'mocks.StatisticsProvider' in the Constr function call [check 'statistics_gateway' service];\n"+
+			"Unknown observer service 'unknown_gateway' for the event 'add_stats_provider'",
+		err,
+		t,
+	)
+}
+
+func TestValidateGraphPanics(t *testing.T) {
+	defer ExpectPanic(t, "Detected dependencies' cycle: user_name->role_name->right_name->user_name")
+
+	ValidateGraph(typedCycleTree[:3])
+}
@@ -0,0 +1,201 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	injectTagName        = "inject"
+	optionalInjectOption = "optional"
+)
+
+//injectedField is a struct field filled with a service from the container
+type injectedField struct {
+	index      int
+	name       string
+	fieldType  reflect.Type
+	serviceID  string
+	isOptional bool
+}
+
+//getDependencyName gives the name of the field dependency as it's declared in ServiceNames
+func (f injectedField) getDependencyName() string {
+	if f.isOptional {
+		return optionalDependencyPrefix + f.serviceID
+	}
+
+	return f.serviceID
+}
+
+//Inject fills exported fields of the struct referenced by target with services identified by the inject tag, e.g.
+//`inject:"db"`, `inject:"db,optional"` or `inject:"#stats_provider"`. An optional field is left as is if its service
+//is not declared in the container or gives nil
+func Inject(c Container, target interface{}) error {
+	reflectedTarget := reflect.ValueOf(target)
+	if reflectedTarget.Kind() != reflect.Ptr || reflectedTarget.IsNil() || reflectedTarget.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("A non nil pointer to a struct is expected as the injection target, '%T' is given", target)
+	}
+
+	structType := reflectedTarget.Elem().Type()
+	fields, err := getInjectedFields(structType, structType.String())
+	if err != nil {
+		return err
+	}
+
+	return injectFields(c, reflectedTarget.Elem(), fields, structType.String(), true)
+}
+
+//AddStruct registers a Service identified by id which is a new struct of the same type as the prototype with fields
+//filled as in the Inject function. A pointer prototype gives pointers to new structs, the prototype itself is never
+//changed
+func (rc *RuntimeContainer) AddStruct(id string, prototype interface{}) error {
+	err := assertValidID(id)
+	if err != nil {
+		return err
+	}
+
+	structType, fields, err := getInjectedStruct(prototype, id)
+	if err != nil {
+		return err
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	err = rc.assertNotFrozen(id)
+	if err != nil {
+		return err
+	}
+
+	err = rc.assertNoDuplicates(id)
+	if err != nil {
+		return err
+	}
+
+	isPointer := reflect.TypeOf(prototype).Kind() == reflect.Ptr
+	rc.newFuncConstructors[id] = func(c Container, isCached bool) (interface{}, error) {
+		service := reflect.New(structType)
+		err := injectFields(c, service.Elem(), fields, id, isCached)
+		if err != nil {
+			return nil, err
+		}
+
+		if isPointer {
+			return service.Interface(), nil
+		}
+
+		return service.Elem().Interface(), nil
+	}
+	rc.serviceTypes[id] = reflect.TypeOf(prototype)
+	rc.structFields[id] = fields
+
+	return nil
+}
+
+//getInjectedStruct checks that the prototype is a struct or a pointer to a struct and gives its injected fields
+func getInjectedStruct(prototype interface{}, serviceID string) (reflect.Type, []injectedField, error) {
+	structType := reflect.TypeOf(prototype)
+	if structType != nil && structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf(
+			"A struct or a pointer to a struct is expected, '%T' is given [check '%s' service]",
+			prototype,
+			serviceID,
+		)
+	}
+
+	fields, err := getInjectedFields(structType, serviceID)
+
+	return structType, fields, err
+}
+
+//getInjectedFields reads the inject tags of the struct fields
+func getInjectedFields(structType reflect.Type, serviceID string) ([]injectedField, error) {
+	fields := []injectedField{}
+	errs := []error{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag, isInjected := field.Tag.Lookup(injectTagName)
+		if !isInjected {
+			continue
+		}
+
+		if field.PkgPath != "" {
+			errs = append(errs, fmt.Errorf(
+				"The field '%s' with the inject tag should be exported [check '%s' service]",
+				field.Name,
+				serviceID,
+			))
+			continue
+		}
+
+		options := strings.Split(tag, ",")
+		injected := injectedField{
+			index:     i,
+			name:      field.Name,
+			fieldType: field.Type,
+			serviceID: strings.TrimSpace(options[0]),
+		}
+		if injected.serviceID == "" {
+			errs = append(errs, fmt.Errorf(
+				"The inject tag of the field '%s' should contain a service id [check '%s' service]",
+				field.Name,
+				serviceID,
+			))
+			continue
+		}
+
+		for _, option := range options[1:] {
+			if strings.TrimSpace(option) != optionalInjectOption {
+				errs = append(errs, fmt.Errorf(
+					"Unknown option '%s' in the inject tag of the field '%s' [check '%s' service]",
+					option,
+					field.Name,
+					serviceID,
+				))
+				continue
+			}
+			injected.isOptional = true
+		}
+
+		fields = append(fields, injected)
+	}
+
+	return fields, mergeErrors(errs)
+}
+
+//injectFields sets the fields of the struct to services fetched from the container
+func injectFields(c Container, target reflect.Value, fields []injectedField, serviceID string, isCached bool) error {
+	errs := []error{}
+	for _, field := range fields {
+		dependency, err := c.GetSecure(field.getDependencyName(), isCached)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if field.isOptional && dependency == nil {
+			continue
+		}
+
+		reflectedDependency, err := getValidFunctionArgument(
+			field.fieldType,
+			dependency,
+			field.serviceID,
+			serviceID,
+			isParameterDependency(c, field.serviceID),
+		)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		target.Field(field.index).Set(reflectedDependency)
+	}
+
+	return mergeErrors(errs)
+}
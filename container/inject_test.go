@@ -0,0 +1,181 @@
+package container
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+//booksHandler is a struct with injected dependencies
+type booksHandler struct {
+	Db         *mocks.FakeDb              `inject:"db"`
+	Cache      mocks.Cache                `inject:"cache"`
+	Providers  []mocks.StatisticsProvider `inject:"#stats_provider"`
+	PageSize   int                        `inject:"page_size"`
+	Logger     mocks.Logger               `inject:"logger,optional"`
+	Statistics mocks.StatisticsProvider   `inject:"statistics,optional"`
+	Name       string
+}
+
+func createContainerForInjection() *RuntimeContainer {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func() *mocks.FakeDb { return &mocks.FakeDb{} })
+	cont.AddNewMethod("cache", func() mocks.Cache { return mocks.NewInMemoryCache() })
+	cont.AddNewMethod("pg_stats", func() mocks.StatisticsProvider { return namedStatistics("pg_stats") })
+	cont.AddTag("pg_stats", "stats_provider")
+	RegisterParameters(cont, map[string]interface{}{"page_size": "20"})
+
+	return cont
+}
+
+func TestInject(t *testing.T) {
+	cont := createContainerForInjection()
+
+	handler := booksHandler{Name: "books", Statistics: namedStatistics("default")}
+	assertNoError(Inject(cont, &handler), t)
+
+	if handler.Db != cont.Get("db", true) || handler.Cache != cont.Get("cache", true) {
+		t.Error("Cached services should be injected")
+	}
+	if len(handler.Providers) != 1 || handler.Providers[0] != namedStatistics("pg_stats") {
+		t.Errorf("Tagged services should be injected, but %v is given", handler.Providers)
+	}
+	if handler.PageSize != 20 {
+		t.Errorf("The converted parameter should be injected, but %d is given", handler.PageSize)
+	}
+	if handler.Logger != nil || handler.Statistics != namedStatistics("default") || handler.Name != "books" {
+		t.Error("Optional fields of missing services and fields without tags should be left as is")
+	}
+}
+
+func TestInjectNilDependency(t *testing.T) {
+	cont := createContainerForInjection()
+	cont.AddConstructor("logger", func(c Container) (interface{}, error) {
+		return nil, nil
+	})
+
+	handler := booksHandler{}
+	assertNoError(Inject(cont, &handler), t)
+	if handler.Logger != nil {
+		t.Error("A nil service should be injected as a nil interface")
+	}
+}
+
+func TestInjectErrors(t *testing.T) {
+	cont := createContainerForInjection()
+
+	err := Inject(cont, booksHandler{})
+	assertErrorText("A non nil pointer to a struct is expected as the injection target, 'container.booksHandler' is given", err, t)
+
+	type unexportedFields struct {
+		db *mocks.FakeDb `inject:"db"`
+	}
+	err = Inject(cont, &unexportedFields{})
+	assertErrorText("The field 'db' with the inject tag should be exported [check 'container.unexportedFields' service]", err, t)
+
+	type wrongTags struct {
+		Db    *mocks.FakeDb `inject:""`
+		Cache mocks.Cache   `inject:"cache,lazy"`
+	}
+	err = Inject(cont, &wrongTags{})
+	assertErrorText(
+		"The inject tag of the field 'Db' should contain a service id [check 'container.wrongTags' service];\n"+
+			"Unknown option 'lazy' in the inject tag of the field 'Cache' [check 'container.wrongTags' service]",
+		err,
+		t,
+	)
+
+	type wrongServices struct {
+		Db    mocks.Cache   `inject:"db"`
+		Cache mocks.Cache   `inject:"unknown_cache"`
+		Stats *mocks.FakeDb `inject:"stats,optional"`
+	}
+	cont.AddConstructor("stats", func(c Container) (interface{}, error) {
+		return nil, errors.New("Stats are not available")
+	})
+	err = Inject(cont, &wrongServices{})
+	assertErrorText(
+		"Cannot use the provided dependency 'db' of type '*mocks.FakeDb' as 'mocks.Cache' in the Constr function call "+
+			"[check 'container.wrongServices' service];\n"+
+			"Unknown dependency 'unknown_cache';\n"+
+			"Stats are not available [check 'stats' service]",
+		err,
+		t,
+	)
+}
+
+func TestAddStruct(t *testing.T) {
+	cont := createContainerForInjection()
+	assertNoError(cont.AddStruct("books_handler", &booksHandler{Name: "prototype"}), t)
+	assertNoError(cont.AddStruct("books_handler_value", booksHandler{}), t)
+
+	handler := cont.Get("books_handler", true).(*booksHandler)
+	if handler.Db != cont.Get("db", true) || handler.PageSize != 20 || handler.Name != "" {
+		t.Errorf("A new struct with injected fields is expected, but %v is given", handler)
+	}
+	if cont.Get("books_handler", true) != handler || cont.Get("books_handler", false) == handler {
+		t.Error("A struct service should be cached as other services")
+	}
+
+	handlerValue := cont.Get("books_handler_value", true).(booksHandler)
+	if handlerValue.Cache != cont.Get("cache", true) {
+		t.Error("A struct value should be filled with injected fields")
+	}
+
+	cont.AddAutowiredNewMethod("handler_name", func(handler *booksHandler) string { return "handler" })
+	AssertExpectedDependency(cont, "handler_name", "handler", t)
+
+	err := cont.AddStruct("books_handler", &booksHandler{})
+	assertErrorText("Detected duplicated dependency declaration 'books_handler'", err, t)
+
+	err = cont.AddStruct("db_handler", mocks.NewFakeDb)
+	assertErrorText("A struct or a pointer to a struct is expected, 'func(string) *mocks.FakeDb' is given [check 'db_handler' service]", err, t)
+}
+
+func TestStructFromConfig(t *testing.T) {
+	tree := Tree{
+		Node{ID: "db", NewFunc: func() *mocks.FakeDb { return &mocks.FakeDb{} }},
+		Node{ID: "cache", NewFunc: func() mocks.Cache { return mocks.NewInMemoryCache() }},
+		Node{Parameters: map[string]interface{}{"page_size": "20"}},
+		Node{ID: "books_handler", Struct: &booksHandler{}, Lifetime: Transient},
+	}
+
+	assertNoError(ValidateGraphSecure(tree), t)
+
+	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfigSecure(tree)
+	assertNoError(err, t)
+	handler := cont.Get("books_handler", true).(*booksHandler)
+	if handler.Db != cont.Get("db", true) || handler.PageSize != 20 {
+		t.Errorf("A struct with injected fields is expected, but %v is given", handler)
+	}
+}
+
+func TestStructValidation(t *testing.T) {
+	err := ValidateConfigSecure(Tree{
+		Node{Struct: &booksHandler{}},
+		Node{ID: "db_handler", Struct: mocks.NewFakeDb, ServiceNames: Services{"db"}},
+	})
+	assertErrorText(
+		"The struct should be provided with a service id, see 'Node: {ID: ; ServiceNames: []; Event: {Name: ; Service: ;}; "+
+			"Observer: {Name: ; Event: ;}}';\n"+
+			"Services of a struct should be defined with inject tags, see 'Node: {ID: db_handler; ServiceNames: [db]; "+
+			"Event: {Name: ; Service: ;}; Observer: {Name: ; Event: ;}}';\n"+
+			"A struct or a pointer to a struct is expected, 'func(string) *mocks.FakeDb' is given [check 'db_handler' service]",
+		err,
+		t,
+	)
+
+	err = ValidateGraphSecure(Tree{
+		Node{ID: "db", NewFunc: mocks.NewInMemoryCache},
+		Node{ID: "books_handler", Struct: &booksHandler{}},
+	})
+	assertErrorText(
+		"Cannot use the provided dependency 'db' of type '*mocks.InMemoryCache' as '*mocks.FakeDb' in the Constr function call "+
+			"[check 'books_handler' service];\n"+
+			"Unknown dependency 'cache' [check 'books_handler' service];\n"+
+			"Unknown dependency 'page_size' [check 'books_handler' service]",
+		err,
+		t,
+	)
+}
@@ -0,0 +1,224 @@
+package container
+
+import (
+	"reflect"
+	"sort"
+)
+
+//RegistrationKind tells how a service was declared in the container
+type RegistrationKind string
+
+const (
+	//ConstructorRegistration is a service declared with AddConstructor or SetConstructor
+	ConstructorRegistration RegistrationKind = "constructor"
+	//NewMethodRegistration is a service declared with AddNewMethod or SetNewMethod
+	NewMethodRegistration RegistrationKind = "new method"
+	//AutowiredRegistration is a service declared with AddAutowiredNewMethod or SetAutowiredNewMethod
+	AutowiredRegistration RegistrationKind = "autowired new method"
+	//StructRegistration is a service declared with AddStruct
+	StructRegistration RegistrationKind = "struct"
+	//AliasRegistration is an alias of another service or an interface bound to a service
+	AliasRegistration RegistrationKind = "alias"
+)
+
+//ServiceDescription explains how a service is wired in the container
+type ServiceDescription struct {
+	ID   string
+	Kind RegistrationKind
+	//Type is the declared type of the service, it's nil for constructors as their output is known only at runtime
+	Type reflect.Type
+	//Dependencies are ids of services required by the service and its decorators as they are declared,
+	//dependencies of constructors are unknown, autowired dependencies are given only if they can be found
+	Dependencies []string
+	Tags         []string
+	//Events are names of events which provide the service to observers
+	Events []string
+	//ObservedEvents are names of events which provide dependencies to the service
+	ObservedEvents      []string
+	HasGarbageCollector bool
+	Lifetime            Lifetime
+	//IsCached tells if the service is already created and is taken from the cache
+	IsCached bool
+}
+
+//Services gives sorted ids of all services and aliases declared in the container and its parents
+func (rc *RuntimeContainer) Services() []string {
+	ids := rc.getServiceIDs()
+	knownIDs := map[string]bool{}
+	for _, id := range ids {
+		knownIDs[id] = true
+	}
+
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		for alias := range cont.aliases {
+			if !knownIDs[alias] {
+				ids = append(ids, alias)
+				knownIDs[alias] = true
+			}
+		}
+		cont.mutex.RUnlock()
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+//Instantiated gives sorted ids of services which are created and cached, so they will be reused by the container
+func (rc *RuntimeContainer) Instantiated() []string {
+	ids := []string{}
+	knownIDs := map[string]bool{}
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		cachedIDs := make([]string, 0, len(cont.cache))
+		for id := range cont.cache {
+			cachedIDs = append(cachedIDs, id)
+		}
+		cont.mutex.RUnlock()
+
+		for _, id := range cachedIDs {
+			if !knownIDs[id] && rc.isInstantiated(id) {
+				ids = append(ids, id)
+				knownIDs[id] = true
+			}
+		}
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+//Describe explains how the service or the alias identified by id is wired, fails if it's not declared
+func (rc *RuntimeContainer) Describe(id string) (ServiceDescription, error) {
+	description := ServiceDescription{ID: id}
+	if target, isAlias := rc.findAlias(id); isAlias {
+		description.Kind = AliasRegistration
+		description.Dependencies = []string{target}
+		rc.describeAliasTarget(&description, target)
+	} else {
+		owner := rc.findOwner(id)
+		if owner == nil {
+			return description, &UnknownServiceError{ServiceID: id}
+		}
+		rc.describeService(&description, owner)
+		description.Lifetime = rc.getLifetime(id)
+		description.IsCached = rc.isInstantiated(id)
+	}
+
+	for cont := rc; cont != nil; cont = cont.parent {
+		description.Events = append(description.Events, cont.eventsContainer.getDependencyEvents(id)...)
+		description.ObservedEvents = append(description.ObservedEvents, cont.eventsContainer.getObservedEvents(id)...)
+		description.HasGarbageCollector = description.HasGarbageCollector || cont.garbageCollectors.has(id)
+
+		cont.mutex.RLock()
+		for tag, taggedServices := range cont.tags {
+			for _, taggedService := range taggedServices {
+				if taggedService.ID == id {
+					description.Tags = append(description.Tags, tag)
+				}
+			}
+		}
+		cont.mutex.RUnlock()
+	}
+	description.Tags = getSortedUniqueNames(description.Tags)
+	description.Events = getSortedUniqueNames(description.Events)
+	description.ObservedEvents = getSortedUniqueNames(description.ObservedEvents)
+
+	return description, nil
+}
+
+//describeService fills the kind, the type and dependencies of the service declared in the owner container
+func (rc *RuntimeContainer) describeService(description *ServiceDescription, owner *RuntimeContainer) {
+	id := description.ID
+
+	owner.mutex.RLock()
+	_, isConstructor := owner.constructors[id]
+	declaration, isNewMethod := owner.newMethods[id]
+	fields, isStruct := owner.structFields[id]
+	serviceType := owner.serviceTypes[id]
+	decorators := owner.decorators[id]
+	owner.mutex.RUnlock()
+
+	description.Dependencies = []string{}
+	switch {
+	case isConstructor:
+		description.Kind = ConstructorRegistration
+	case isStruct:
+		description.Kind = StructRegistration
+		description.Type = serviceType
+		for _, field := range fields {
+			description.Dependencies = append(description.Dependencies, field.getDependencyName())
+		}
+	case isNewMethod && declaration.isAutowired:
+		description.Kind = AutowiredRegistration
+		description.Type = serviceType
+		//autowired dependencies are looked up in the current scope as they are at the service creation
+		argumentNames, err := rc.autowireArguments(declaration.newMethod, id)
+		if err == nil {
+			description.Dependencies = append(description.Dependencies, argumentNames...)
+		}
+	default:
+		description.Kind = NewMethodRegistration
+		description.Type = serviceType
+		if isNewMethod {
+			description.Dependencies = append(description.Dependencies, declaration.argumentNames...)
+		}
+	}
+
+	for _, decorator := range decorators {
+		description.Dependencies = append(description.Dependencies, decorator.argumentNames...)
+	}
+}
+
+//describeAliasTarget fills the type, the lifetime and the cache state of the alias from the service it refers to
+//through the aliases chain, nothing is filled if the service is not declared
+func (rc *RuntimeContainer) describeAliasTarget(description *ServiceDescription, target string) {
+	visitedAliases := map[string]bool{description.ID: true}
+	for !visitedAliases[target] {
+		visitedAliases[target] = true
+		nextTarget, isAlias := rc.findAlias(target)
+		if !isAlias {
+			break
+		}
+		target = nextTarget
+	}
+
+	if rc.findOwner(target) == nil {
+		return
+	}
+
+	description.Type, _ = rc.findServiceType(target)
+	description.Lifetime = rc.getLifetime(target)
+	description.IsCached = rc.isInstantiated(target)
+}
+
+//isInstantiated tells if the cached service will be reused by the container, a scoped service cached in a parent
+//container is not reused by its scopes
+func (rc *RuntimeContainer) isInstantiated(id string) bool {
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		_, isCached := cont.cache.Get(id)
+		cont.mutex.RUnlock()
+
+		if isCached && (cont == rc || rc.getLifetime(id) != Scoped) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//getSortedUniqueNames sorts names and removes duplicates
+func getSortedUniqueNames(names []string) []string {
+	uniqueNames := []string{}
+	knownNames := map[string]bool{}
+	for _, name := range names {
+		if !knownNames[name] {
+			uniqueNames = append(uniqueNames, name)
+			knownNames[name] = true
+		}
+	}
+	sort.Strings(uniqueNames)
+
+	return uniqueNames
+}
@@ -0,0 +1,186 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+//formatDescription gives a comparable text of the service description
+func formatDescription(description ServiceDescription) string {
+	return fmt.Sprintf(
+		"%s: kind=%s; type=%v; dependencies=%s; tags=%s; events=%s; observed=%s; gc=%t; lifetime=%s; cached=%t",
+		description.ID,
+		description.Kind,
+		description.Type,
+		strings.Join(description.Dependencies, ","),
+		strings.Join(description.Tags, ","),
+		strings.Join(description.Events, ","),
+		strings.Join(description.ObservedEvents, ","),
+		description.HasGarbageCollector,
+		description.Lifetime,
+		description.IsCached,
+	)
+}
+
+func assertDescription(c Introspector, id, expectedDescription string, t *testing.T) {
+	t.Helper()
+
+	description, err := c.Describe(id)
+	assertNoError(err, t)
+	if formatDescription(description) != expectedDescription {
+		t.Errorf("Description '%s' is expected, but '%s' is given", expectedDescription, formatDescription(description))
+	}
+}
+
+func assertIDs(expectedIDs string, ids []string, t *testing.T) {
+	t.Helper()
+
+	if strings.Join(ids, ",") != expectedIDs {
+		t.Errorf("Services '%s' are expected, but '%s' are given", expectedIDs, strings.Join(ids, ","))
+	}
+}
+
+func TestExistsNewMethods(t *testing.T) {
+	cont := CreateContainer()
+	cont.AddStruct("books_handler", &booksHandler{})
+	for _, id := range []string{"book_storage", "config", "book_downloader", "books_handler"} {
+		if !cont.Exists(id) {
+			t.Errorf("The service '%s' should exist", id)
+		}
+	}
+}
+
+func TestServices(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("in_memory_cache", mocks.NewInMemoryCache)
+	cont.AddConstructor("db", func(c Container) (interface{}, error) {
+		return &mocks.FakeDb{}, nil
+	})
+	cont.Alias("cache", "in_memory_cache")
+
+	scope := cont.NewScope()
+	scope.AddNewMethod("cache_manager", mocks.NewCacheManager, "cache")
+	scope.AddNewMethod("db", func() *mocks.FakeDb { return &mocks.FakeDb{} })
+
+	assertIDs("cache,db,in_memory_cache", cont.Services(), t)
+	assertIDs("cache,cache_manager,db,in_memory_cache", scope.Services(), t)
+}
+
+func TestDescribe(t *testing.T) {
+	cont := CreateContainer()
+	cont.AddGarbageCollectFunc("db", func(service interface{}) error {
+		return nil
+	})
+	cont.AddStruct("books_handler", &booksHandler{})
+	cont.AddAutowiredNewMethod("cache_manager", mocks.NewCacheManager)
+	cont.Decorate("cache_manager", func(manager mocks.CacheManager, config mocks.Config) mocks.CacheManager {
+		return manager
+	}, "config")
+	cont.AddTag("book_storage", "stats_provider")
+	cont.Alias("storage", "book_storage")
+	cont.Alias("books", "storage")
+	cont.SetLifetime("authors_storage", Transient)
+	cont.Get("book_storage", true)
+
+	assertDescription(
+		cont,
+		"book_storage",
+		"book_storage: kind=constructor; type=<nil>; dependencies=; tags=stats_provider; events=statistics_provider; "+
+			"observed=; gc=false; lifetime=default; cached=true",
+		t,
+	)
+	assertDescription(
+		cont,
+		"book_downloader",
+		"book_downloader: kind=new method; type=*mocks.BookDownloader; "+
+			"dependencies=in_memory_cache,book_link_provider,book_finder,web_fetcher; tags=; events=; observed=; "+
+			"gc=false; lifetime=default; cached=false",
+		t,
+	)
+	assertDescription(
+		cont,
+		"authors_storage",
+		"authors_storage: kind=new method; type=mocks.AuthorsStorage; dependencies=db; tags=; "+
+			"events=statistics_provider; observed=; gc=false; lifetime=transient; cached=false",
+		t,
+	)
+	assertDescription(
+		cont,
+		"statistics_gateway",
+		"statistics_gateway: kind=new method; type=*mocks.StatisticsGateway; dependencies=; tags=; events=; "+
+			"observed=statistics_provider; gc=false; lifetime=default; cached=false",
+		t,
+	)
+	assertDescription(
+		cont,
+		"db",
+		"db: kind=constructor; type=<nil>; dependencies=; tags=; events=; observed=; gc=true; lifetime=default; cached=true",
+		t,
+	)
+	assertDescription(
+		cont,
+		"books_handler",
+		"books_handler: kind=struct; type=*container.booksHandler; "+
+			"dependencies=db,cache,#stats_provider,page_size,?logger,?statistics; tags=; events=; observed=; gc=false; "+
+			"lifetime=default; cached=false",
+		t,
+	)
+	assertDescription(
+		cont,
+		"cache_manager",
+		"cache_manager: kind=autowired new method; type=mocks.CacheManager; dependencies=in_memory_cache,config; "+
+			"tags=; events=; observed=; gc=false; lifetime=default; cached=false",
+		t,
+	)
+	assertDescription(
+		cont,
+		"books",
+		"books: kind=alias; type=<nil>; dependencies=storage; tags=; events=; observed=; gc=false; lifetime=default; "+
+			"cached=true",
+		t,
+	)
+
+	_, err := cont.Describe("unknown_service")
+	assertErrorText("Unknown dependency 'unknown_service'", err, t)
+}
+
+func TestDescribeInScope(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("cache", mocks.NewInMemoryCache)
+	cont.AddNewMethod("cache_manager", mocks.NewCacheManager, "cache")
+	cont.SetLifetime("cache_manager", Scoped)
+	cont.Get("cache_manager", true)
+
+	scope := cont.NewScope()
+	scope.AddNewMethod("db", func() *mocks.FakeDb { return &mocks.FakeDb{} })
+	scope.Get("db", true)
+
+	assertIDs("cache,cache_manager", cont.Instantiated(), t)
+	assertIDs("cache,db", scope.Instantiated(), t)
+
+	description, err := scope.Describe("cache_manager")
+	assertNoError(err, t)
+	if description.IsCached || description.Lifetime != Scoped || description.Type != reflect.TypeOf(mocks.CacheManager{}) {
+		t.Errorf("A scoped service cached in the parent container is not reused by the scope, but %v is given", description)
+	}
+
+	scope.Get("cache_manager", true)
+	assertIDs("cache,cache_manager,db", scope.Instantiated(), t)
+
+	_, err = cont.Describe("db")
+	assertErrorText("Unknown dependency 'db'", err, t)
+}
+
+func TestConstructorsCanIntrospectContainer(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("in_memory_cache", mocks.NewInMemoryCache)
+	cont.AddConstructor("service_ids", func(c Container) (interface{}, error) {
+		return c.(Introspector).Services(), nil
+	})
+
+	assertIDs("in_memory_cache,service_ids", cont.Get("service_ids", true).([]string), t)
+}
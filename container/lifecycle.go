@@ -0,0 +1,313 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//LifecycleHook starts or stops a created service, the context is cancelled when the hook timeout is exceeded
+type LifecycleHook func(ctx context.Context, service interface{}) error
+
+type timedLifecycleHook struct {
+	hook    LifecycleHook
+	timeout time.Duration
+}
+
+//lifecycleHooks holds start and stop hooks of services, started and stopped services are identified by their
+//creation numbers, so hooks of the same instance aren't called twice while a recreated service gets them again
+type lifecycleHooks struct {
+	startHooks    map[string][]timedLifecycleHook
+	stopHooks     map[string][]timedLifecycleHook
+	startServices []string
+	started       map[string]uint64
+	stopped       map[string]uint64
+	mutex         sync.RWMutex
+}
+
+func newLifecycleHooks() *lifecycleHooks {
+	return &lifecycleHooks{
+		startHooks:    map[string][]timedLifecycleHook{},
+		stopHooks:     map[string][]timedLifecycleHook{},
+		startServices: []string{},
+		started:       map[string]uint64{},
+		stopped:       map[string]uint64{},
+	}
+}
+
+//copy gives an independent copy of lifecycle hooks
+func (lh *lifecycleHooks) copy() *lifecycleHooks {
+	lh.mutex.RLock()
+	defer lh.mutex.RUnlock()
+
+	hooksCopy := newLifecycleHooks()
+	for id, hooks := range lh.startHooks {
+		hooksCopy.startHooks[id] = append([]timedLifecycleHook{}, hooks...)
+	}
+	for id, hooks := range lh.stopHooks {
+		hooksCopy.stopHooks[id] = append([]timedLifecycleHook{}, hooks...)
+	}
+	hooksCopy.startServices = append(hooksCopy.startServices, lh.startServices...)
+	hooksCopy.started = copyMap(lh.started)
+	hooksCopy.stopped = copyMap(lh.stopped)
+
+	return hooksCopy
+}
+
+//replace sets lifecycle hooks to a copy of the provided ones
+func (lh *lifecycleHooks) replace(source *lifecycleHooks) {
+	sourceCopy := source.copy()
+
+	lh.mutex.Lock()
+	defer lh.mutex.Unlock()
+
+	lh.startHooks = sourceCopy.startHooks
+	lh.stopHooks = sourceCopy.stopHooks
+	lh.startServices = sourceCopy.startServices
+	lh.started = sourceCopy.started
+	lh.stopped = sourceCopy.stopped
+}
+
+//markStarted claims not started services for start hooks, it gives only the claimed services
+func (lh *lifecycleHooks) markStarted(services []startedService) []startedService {
+	lh.mutex.Lock()
+	defer lh.mutex.Unlock()
+
+	claimedServices := []startedService{}
+	for _, s := range services {
+		if creationNumber, isStarted := lh.started[s.id]; isStarted && creationNumber == s.creationNumber {
+			continue
+		}
+		lh.started[s.id] = s.creationNumber
+		delete(lh.stopped, s.id)
+		claimedServices = append(claimedServices, s)
+	}
+
+	return claimedServices
+}
+
+//markStopped claims not stopped services for stop hooks, it gives only the claimed services
+func (lh *lifecycleHooks) markStopped(services []startedService) []startedService {
+	lh.mutex.Lock()
+	defer lh.mutex.Unlock()
+
+	claimedServices := []startedService{}
+	for _, s := range services {
+		if creationNumber, isStopped := lh.stopped[s.id]; isStopped && creationNumber == s.creationNumber {
+			continue
+		}
+		lh.stopped[s.id] = s.creationNumber
+		delete(lh.started, s.id)
+		claimedServices = append(claimedServices, s)
+	}
+
+	return claimedServices
+}
+
+//creationsCounter gives increasing numbers to created services, so that they can be stopped in the reverse order
+var creationsCounter uint64
+
+func nextCreationNumber() uint64 {
+	return atomic.AddUint64(&creationsCounter, 1)
+}
+
+type startedService struct {
+	id             string
+	service        interface{}
+	creationNumber uint64
+}
+
+//OnStart registers a hook called by the Start function after the creation of the service identified by id,
+//a zero timeout means that the hook is limited only by the context of the Start call
+func (rc *RuntimeContainer) OnStart(id string, hook LifecycleHook, timeout time.Duration) {
+	rc.lifecycleHooks.mutex.Lock()
+	defer rc.lifecycleHooks.mutex.Unlock()
+
+	if _, exists := rc.lifecycleHooks.startHooks[id]; !exists {
+		rc.lifecycleHooks.startServices = append(rc.lifecycleHooks.startServices, id)
+	}
+	rc.lifecycleHooks.startHooks[id] = append(rc.lifecycleHooks.startHooks[id], timedLifecycleHook{hook: hook, timeout: timeout})
+}
+
+//OnStop registers a hook called by the Stop function if the service identified by id was created,
+//a zero timeout means that the hook is limited only by the context of the Stop call
+func (rc *RuntimeContainer) OnStop(id string, hook LifecycleHook, timeout time.Duration) {
+	rc.lifecycleHooks.mutex.Lock()
+	defer rc.lifecycleHooks.mutex.Unlock()
+
+	rc.lifecycleHooks.stopHooks[id] = append(rc.lifecycleHooks.stopHooks[id], timedLifecycleHook{hook: hook, timeout: timeout})
+}
+
+//Start creates all services with start hooks and calls the hooks in the order of services creation, so dependencies
+//are started before their consumers. Services started by a previous call and not stopped since are skipped. If a hook
+//fails, the services started by this call are stopped in the reverse order
+func (rc *RuntimeContainer) Start(ctx context.Context) error {
+	errs := []error{}
+	services := []startedService{}
+	for _, id := range rc.getStartServices() {
+		service, err := rc.GetContext(ctx, id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		services = append(services, startedService{id: id, service: service, creationNumber: rc.getCreationNumber(id)})
+	}
+
+	if len(errs) > 0 {
+		return mergeErrors(errs)
+	}
+
+	sort.SliceStable(services, func(i, j int) bool {
+		return services[i].creationNumber < services[j].creationNumber
+	})
+
+	services = rc.lifecycleHooks.markStarted(services)
+	for i, s := range services {
+		err := runLifecycleHooks(ctx, "Start", s.id, s.service, rc.getLifecycleHooks(s.id, true))
+		if err != nil {
+			rc.lifecycleHooks.markStopped(services[i : i+1])
+			return mergeErrors([]error{err, rc.stopServices(ctx, services[:i])})
+		}
+	}
+
+	return nil
+}
+
+//Stop calls stop hooks only for services created and cached in the container in the reverse order of their creation,
+//so consumers are stopped before their dependencies. All hooks are called even if some of them fail. Services stopped
+//by a previous call are skipped, so Stop can be called several times. Instances which are not kept in the cache are
+//unknown to the container and never stopped: transient services and instances replaced by a later non cached fetch
+func (rc *RuntimeContainer) Stop(ctx context.Context) error {
+	rc.mutex.RLock()
+	services := make([]startedService, 0, len(rc.cache))
+	for id, service := range rc.cache {
+		services = append(services, startedService{id: id, service: service, creationNumber: rc.creationNumbers[id]})
+	}
+	rc.mutex.RUnlock()
+
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].creationNumber == services[j].creationNumber {
+			return services[i].id > services[j].id
+		}
+		return services[i].creationNumber < services[j].creationNumber
+	})
+
+	return rc.stopServices(ctx, services)
+}
+
+//stopServices calls stop hooks of not stopped services in the reverse order of the provided ones
+func (rc *RuntimeContainer) stopServices(ctx context.Context, services []startedService) error {
+	services = rc.lifecycleHooks.markStopped(services)
+
+	errs := []error{}
+	for i := len(services) - 1; i >= 0; i-- {
+		s := services[i]
+		err := runLifecycleHooks(ctx, "Stop", s.id, s.service, rc.getLifecycleHooks(s.id, false))
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return mergeErrors(errs)
+}
+
+//Run starts the container, blocks until the context is cancelled and then stops the container, stop hooks
+//get a new context as the provided one is already cancelled
+func (rc *RuntimeContainer) Run(ctx context.Context) error {
+	err := rc.Start(ctx)
+	if err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+
+	return rc.Stop(context.Background())
+}
+
+func runLifecycleHooks(ctx context.Context, stage, id string, service interface{}, hooks []timedLifecycleHook) error {
+	errs := []error{}
+	for _, hook := range hooks {
+		err := runLifecycleHook(ctx, hook, service)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s hook failed: %w [check '%s' service]", stage, err, id))
+		}
+	}
+
+	return mergeErrors(errs)
+}
+
+//runLifecycleHook stops waiting for a hook when its timeout is exceeded even if the hook ignores the context
+func runLifecycleHook(ctx context.Context, hook timedLifecycleHook, service interface{}) error {
+	if hook.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hook.timeout)
+		defer cancel()
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- hook.hook(ctx, service)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//getStartServices gives ids of services with start hooks in the scopes chain in the order of hooks registration
+func (rc *RuntimeContainer) getStartServices() []string {
+	ids := []string{}
+	knownIDs := map[string]bool{}
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.lifecycleHooks.mutex.RLock()
+		for _, id := range cont.lifecycleHooks.startServices {
+			if !knownIDs[id] {
+				knownIDs[id] = true
+				ids = append(ids, id)
+			}
+		}
+		cont.lifecycleHooks.mutex.RUnlock()
+	}
+
+	return ids
+}
+
+//getLifecycleHooks gives start or stop hooks of a service declared in the closest container of the scopes chain
+func (rc *RuntimeContainer) getLifecycleHooks(id string, isStart bool) []timedLifecycleHook {
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.lifecycleHooks.mutex.RLock()
+		hooks, exists := cont.lifecycleHooks.stopHooks[id]
+		if isStart {
+			hooks, exists = cont.lifecycleHooks.startHooks[id]
+		}
+		cont.lifecycleHooks.mutex.RUnlock()
+
+		if exists {
+			return hooks
+		}
+	}
+
+	return nil
+}
+
+//getCreationNumber gives the creation number of a cached service in the closest container of the scopes chain
+func (rc *RuntimeContainer) getCreationNumber(id string) uint64 {
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		creationNumber, exists := cont.creationNumbers[id]
+		cont.mutex.RUnlock()
+
+		if exists {
+			return creationNumber
+		}
+	}
+
+	return 0
+}
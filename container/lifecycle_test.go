@@ -0,0 +1,202 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+type lifecycleRecorder struct {
+	calls []string
+	mutex sync.Mutex
+}
+
+func (lr *lifecycleRecorder) hook(call string) LifecycleHook {
+	return func(ctx context.Context, service interface{}) error {
+		lr.mutex.Lock()
+		defer lr.mutex.Unlock()
+
+		lr.calls = append(lr.calls, call)
+		return nil
+	}
+}
+
+func (lr *lifecycleRecorder) countCalls() int {
+	lr.mutex.Lock()
+	defer lr.mutex.Unlock()
+
+	return len(lr.calls)
+}
+
+func (lr *lifecycleRecorder) assertCalls(expectedCalls string, t *testing.T) {
+	lr.mutex.Lock()
+	defer lr.mutex.Unlock()
+
+	actualCalls := strings.Join(lr.calls, ",")
+	if actualCalls != expectedCalls {
+		t.Errorf("Expected lifecycle calls '%s' but '%s' were done", expectedCalls, actualCalls)
+	}
+}
+
+func createContainerWithLifecycleHooks(recorder *lifecycleRecorder) *RuntimeContainer {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("book_finder", mocks.NewBookFinder, "book_storage", "book_creator")
+	cont.AddNewMethod("book_creator", func() mocks.BookCreator { return mocks.BookCreator{} })
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	cont.AddNewMethod("db", func() *mocks.FakeDb { return mocks.NewFakeDb("someConnectionString") })
+	cont.AddNewMethod("statistics_gateway", mocks.NewStatisticsGateway)
+
+	for _, id := range []string{"book_finder", "book_storage", "db"} {
+		cont.OnStart(id, recorder.hook("start "+id), 0)
+		cont.OnStop(id, recorder.hook("stop "+id), 0)
+	}
+	cont.OnStop("statistics_gateway", recorder.hook("stop statistics_gateway"), 0)
+
+	return cont
+}
+
+func TestLifecycleHooksOrder(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	cont := createContainerWithLifecycleHooks(recorder)
+
+	err := cont.Start(context.Background())
+	assertNoError(err, t)
+	recorder.assertCalls("start db,start book_storage,start book_finder", t)
+
+	err = cont.Stop(context.Background())
+	assertNoError(err, t)
+	recorder.assertCalls(
+		"start db,start book_storage,start book_finder,stop book_finder,stop book_storage,stop db",
+		t,
+	)
+}
+
+func TestStopHooksOfNotCreatedServices(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	cont := createContainerWithLifecycleHooks(recorder)
+	cont.Get("book_storage", true)
+
+	err := cont.Stop(context.Background())
+	assertNoError(err, t)
+	recorder.assertCalls("stop book_storage,stop db", t)
+}
+
+func TestLifecycleHookTimeout(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func() *mocks.FakeDb { return mocks.NewFakeDb("someConnectionString") })
+	cont.Get("db", true)
+
+	blockingHookIsReleased := make(chan bool)
+	defer close(blockingHookIsReleased)
+	cont.OnStop("db", func(ctx context.Context, service interface{}) error {
+		<-blockingHookIsReleased
+		return nil
+	}, 10*time.Millisecond)
+
+	err := cont.Stop(context.Background())
+	assertErrorText("Stop hook failed: context deadline exceeded [check 'db' service]", err, t)
+}
+
+func TestFailedStartStopsStartedServices(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	cont := createContainerWithLifecycleHooks(recorder)
+	cont.OnStart("book_storage", func(ctx context.Context, service interface{}) error {
+		return errors.New("Cannot open the storage")
+	}, 0)
+
+	err := cont.Start(context.Background())
+	assertErrorText("Start hook failed: Cannot open the storage [check 'book_storage' service]", err, t)
+	recorder.assertCalls("start db,start book_storage,stop db", t)
+
+	err = cont.Stop(context.Background())
+	assertNoError(err, t)
+	recorder.assertCalls("start db,start book_storage,stop db,stop book_finder", t)
+}
+
+func TestRepeatedStartAndStop(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	cont := createContainerWithLifecycleHooks(recorder)
+
+	for i := 0; i < 2; i++ {
+		err := cont.Start(context.Background())
+		assertNoError(err, t)
+	}
+	recorder.assertCalls("start db,start book_storage,start book_finder", t)
+
+	for i := 0; i < 2; i++ {
+		err := cont.Stop(context.Background())
+		assertNoError(err, t)
+	}
+	recorder.assertCalls(
+		"start db,start book_storage,start book_finder,stop book_finder,stop book_storage,stop db",
+		t,
+	)
+
+	err := cont.Start(context.Background())
+	assertNoError(err, t)
+	recorder.assertCalls(
+		"start db,start book_storage,start book_finder,stop book_finder,stop book_storage,stop db,"+
+			"start db,start book_storage,start book_finder",
+		t,
+	)
+}
+
+func TestStopSkipsNotCachedInstances(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	cont := createContainerWithLifecycleHooks(recorder)
+	cont.Get("book_finder", false)
+	cont.Get("book_finder", false)
+	cont.SetLifetime("statistics_gateway", Transient)
+	cont.Get("statistics_gateway", true)
+
+	err := cont.Stop(context.Background())
+	assertNoError(err, t)
+	recorder.assertCalls("stop book_finder,stop book_storage,stop db", t)
+}
+
+func TestRunStopsOnContextCancellation(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	cont := createContainerWithLifecycleHooks(recorder)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runResult := make(chan error)
+	go func() {
+		runResult <- cont.Run(ctx)
+	}()
+
+	for i := 0; i < 100 && recorder.countCalls() < 3; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	recorder.assertCalls("start db,start book_storage,start book_finder", t)
+
+	cancel()
+	assertNoError(<-runResult, t)
+	recorder.assertCalls(
+		"start db,start book_storage,start book_finder,stop book_finder,stop book_storage,stop db",
+		t,
+	)
+}
+
+func TestScopeStopsOnlyItsServices(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	cont := createContainerWithScopedServices()
+	cont.OnStop("db", recorder.hook("stop db"), 0)
+	cont.OnStop("transaction", recorder.hook("stop transaction"), 0)
+
+	scope := cont.NewScope()
+	scope.Get("book_storage", true)
+	scope.Get("db", true)
+
+	err := scope.Stop(context.Background())
+	assertNoError(err, t)
+	recorder.assertCalls("stop transaction", t)
+
+	err = cont.Stop(context.Background())
+	assertNoError(err, t)
+	recorder.assertCalls("stop transaction,stop db", t)
+}
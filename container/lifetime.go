@@ -0,0 +1,97 @@
+package container
+
+import "fmt"
+
+//Lifetime defines how long a created service is reused
+type Lifetime int
+
+const (
+	//DefaultLifetime means that the isCached flag of the caller decides if the service is taken from the cache
+	DefaultLifetime Lifetime = iota
+	//Singleton services are created once and are always taken from the cache
+	Singleton
+	//Transient services are created every time they are requested and are never cached
+	Transient
+	//Scoped services are created once per scope, in a container without scopes they behave as singletons
+	Scoped
+)
+
+func (l Lifetime) String() string {
+	switch l {
+	case DefaultLifetime:
+		return "default"
+	case Singleton:
+		return "singleton"
+	case Transient:
+		return "transient"
+	case Scoped:
+		return "scoped"
+	default:
+		return "unknown"
+	}
+}
+
+//isCached tells if a service with this lifetime should be taken from the cache, when the caller asks
+//for a cached (isCachedByCaller = true) or a non cached version
+func (l Lifetime) isCached(isCachedByCaller bool) bool {
+	switch l {
+	case Singleton, Scoped:
+		return true
+	case Transient:
+		return false
+	default:
+		return isCachedByCaller
+	}
+}
+
+//SetLifetime declares the lifetime of a service identified by id, it's respected no matter how the service is fetched,
+//panics if the container is frozen
+func (rc *RuntimeContainer) SetLifetime(id string, lifetime Lifetime) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	err := rc.assertNotFrozen(id)
+	if err != nil {
+		panic(err)
+	}
+
+	if lifetime == DefaultLifetime {
+		delete(rc.lifetimes, id)
+		return
+	}
+
+	rc.lifetimes[id] = lifetime
+}
+
+//getLifetime gives the declared lifetime of a service in the scopes chain or DefaultLifetime if none was declared
+func (rc *RuntimeContainer) getLifetime(id string) Lifetime {
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		lifetime, ok := cont.lifetimes[id]
+		cont.mutex.RUnlock()
+
+		if ok {
+			return lifetime
+		}
+	}
+
+	return DefaultLifetime
+}
+
+//parseLifetime gives a lifetime by its name, e.g. "singleton"
+func parseLifetime(name string) (Lifetime, error) {
+	for _, lifetime := range []Lifetime{DefaultLifetime, Singleton, Transient, Scoped} {
+		if lifetime.String() == name {
+			return lifetime, nil
+		}
+	}
+
+	return DefaultLifetime, fmt.Errorf(
+		"Unknown lifetime '%s', expected lifetimes are %s, %s, %s and %s",
+		name,
+		DefaultLifetime,
+		Singleton,
+		Transient,
+		Scoped,
+	)
+}
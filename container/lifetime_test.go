@@ -0,0 +1,129 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func createContainerWithCountedDb(lifetime Lifetime) (*RuntimeContainer, *int) {
+	dbCreationsCount := 0
+	cont := NewRuntimeContainer()
+	cont.AddConstructor("db", func(c Container) (interface{}, error) {
+		dbCreationsCount++
+		return mocks.NewFakeDb("someConnectionString"), nil
+	})
+	cont.SetLifetime("db", lifetime)
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+
+	return cont, &dbCreationsCount
+}
+
+func TestSingletonIsNotRebuiltForNonCachedConsumer(t *testing.T) {
+	cont, dbCreationsCount := createContainerWithCountedDb(Singleton)
+
+	cont.Get("book_storage", false)
+	cont.Get("book_storage", false)
+	cont.Get("db", false)
+
+	if *dbCreationsCount != 1 {
+		t.Errorf("Singleton 'db' should be created once, but it was created %d times", *dbCreationsCount)
+	}
+}
+
+func TestTransientIsRebuiltForCachedRequests(t *testing.T) {
+	cont, dbCreationsCount := createContainerWithCountedDb(Transient)
+
+	firstDb := cont.Get("db", true).(*mocks.FakeDb)
+	secondDb := cont.Get("db", true).(*mocks.FakeDb)
+	if firstDb == secondDb {
+		t.Error("Transient 'db' should be a new instance on every request")
+	}
+
+	cont.Get("book_storage", true)
+	cont.Get("book_storage", true)
+
+	if *dbCreationsCount != 3 {
+		t.Errorf("Transient 'db' should be created 3 times, but it was created %d times", *dbCreationsCount)
+	}
+}
+
+func TestSharedDependencyOfTransientIsBuiltOnce(t *testing.T) {
+	for _, lifetime := range []Lifetime{DefaultLifetime, Singleton} {
+		cont, dbCreationsCount := createContainerWithCountedDb(lifetime)
+		cont.SetLifetime("book_storage", Transient)
+
+		cont.Get("book_storage", true)
+		cont.Get("book_storage", true)
+
+		if *dbCreationsCount != 1 {
+			t.Errorf(
+				"'%s' dependency 'db' of a transient service should be created once, but it was created %d times",
+				lifetime,
+				*dbCreationsCount,
+			)
+		}
+	}
+}
+
+func TestDefaultLifetimeFollowsCallerFlag(t *testing.T) {
+	cont, dbCreationsCount := createContainerWithCountedDb(DefaultLifetime)
+
+	cont.Get("book_storage", true)
+	cont.Get("book_storage", true)
+	cont.Get("book_storage", false)
+
+	if *dbCreationsCount != 2 {
+		t.Errorf("'db' should be created 2 times, but it was created %d times", *dbCreationsCount)
+	}
+}
+
+func TestLifetimeInConfig(t *testing.T) {
+	tree := Tree{
+		Node{ID: "book_shelve", NewFunc: mocks.NewBookShelve, Lifetime: Transient},
+		Node{ID: "in_memory_cache", NewFunc: mocks.NewInMemoryCache, Lifetime: Singleton},
+	}
+
+	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfig(tree)
+	assertNoError(err, t)
+
+	if cont.Get("book_shelve", true) == cont.Get("book_shelve", true) {
+		t.Error("Transient 'book_shelve' should be a new instance on every request")
+	}
+
+	if cont.Get("in_memory_cache", false) != cont.Get("in_memory_cache", false) {
+		t.Error("Singleton 'in_memory_cache' should be the same instance on every request")
+	}
+}
+
+func TestLifetimeConfigValidation(t *testing.T) {
+	tree := Tree{
+		Node{
+			Parameters: map[string]interface{}{"param1": "value1"},
+			Lifetime:   Singleton,
+		},
+	}
+
+	err := ValidateConfigSecure(tree)
+	assertErrorText(
+		"Lifetime should be defined with a non empty new func or constructor, see 'Node: {ID: ; ServiceNames: []; Event: {Name: ; Service: ;}; Observer: {Name: ; Event: ;}}'",
+		err,
+		t,
+	)
+}
+
+func TestLifetimeString(t *testing.T) {
+	lifetimes := map[Lifetime]string{
+		DefaultLifetime: "default",
+		Singleton:       "singleton",
+		Transient:       "transient",
+		Scoped:          "scoped",
+		Lifetime(100):   "unknown",
+	}
+
+	for lifetime, expectedName := range lifetimes {
+		if lifetime.String() != expectedName {
+			t.Errorf("Lifetime name '%s' is expected, but '%s' is given", expectedName, lifetime)
+		}
+	}
+}
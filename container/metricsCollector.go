@@ -0,0 +1,100 @@
+package container
+
+import (
+	"sync"
+	"time"
+)
+
+//ResolutionMetrics are aggregated resolutions of a service
+type ResolutionMetrics struct {
+	//Resolutions is the number of all requests of the service including the ones served from the cache
+	Resolutions int
+	//Creations is the number of requests which created the service
+	Creations int
+	Errors    int
+	//TotalDuration and MaxDuration include the resolution time of dependencies of the service
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+}
+
+//MetricsCollector is a resolution hook which aggregates resolutions of services and dispatched events in memory
+type MetricsCollector struct {
+	services         map[string]ResolutionMetrics
+	dispatchedEvents map[string]int
+	mutex            sync.Mutex
+}
+
+//NewMetricsCollector creates an empty metrics collector
+func NewMetricsCollector() *MetricsCollector {
+	return &MetricsCollector{
+		services:         map[string]ResolutionMetrics{},
+		dispatchedEvents: map[string]int{},
+	}
+}
+
+//OnResolveStart does nothing as only finished resolutions are counted
+func (mc *MetricsCollector) OnResolveStart(id string) {
+}
+
+//OnResolveEnd adds the resolution to the metrics of the service
+func (mc *MetricsCollector) OnResolveEnd(id string, duration time.Duration, err error, fromCache bool) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	metrics := mc.services[id]
+	metrics.Resolutions++
+	metrics.TotalDuration += duration
+	if duration > metrics.MaxDuration {
+		metrics.MaxDuration = duration
+	}
+
+	if err != nil {
+		metrics.Errors++
+	} else if !fromCache {
+		metrics.Creations++
+	}
+	mc.services[id] = metrics
+}
+
+//OnEventDispatched counts dispatched dependencies of the event
+func (mc *MetricsCollector) OnEventDispatched(event, observer, dependency string) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	mc.dispatchedEvents[event]++
+}
+
+//Metrics gives a copy of collected metrics by service ids
+func (mc *MetricsCollector) Metrics() map[string]ResolutionMetrics {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	services := make(map[string]ResolutionMetrics, len(mc.services))
+	for id, metrics := range mc.services {
+		services[id] = metrics
+	}
+
+	return services
+}
+
+//DispatchedEvents gives a copy of numbers of dependencies provided to observers by event names
+func (mc *MetricsCollector) DispatchedEvents() map[string]int {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	dispatchedEvents := make(map[string]int, len(mc.dispatchedEvents))
+	for event, count := range mc.dispatchedEvents {
+		dispatchedEvents[event] = count
+	}
+
+	return dispatchedEvents
+}
+
+//Reset removes all collected metrics
+func (mc *MetricsCollector) Reset() {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	mc.services = map[string]ResolutionMetrics{}
+	mc.dispatchedEvents = map[string]int{}
+}
@@ -0,0 +1,61 @@
+package container
+
+import "strings"
+
+const (
+	optionalDependencyPrefix    = "?"
+	defaultDependencySeparator = "|"
+)
+
+//optionalDependency is a dependency which might be not declared in the container, it's declared as "?id" to get nil or
+//the zero value of the argument or as "id|default" to get the default value converted to the argument type
+type optionalDependency struct {
+	id           string
+	defaultValue string
+	hasDefault   bool
+}
+
+//parseOptionalDependency tells if the dependency name refers to an optional dependency
+func parseOptionalDependency(dependencyName string) (optionalDependency, bool) {
+	dependency := optionalDependency{id: dependencyName}
+	isOptional := false
+	if strings.HasPrefix(dependency.id, optionalDependencyPrefix) {
+		dependency.id = strings.TrimPrefix(dependency.id, optionalDependencyPrefix)
+		isOptional = true
+	}
+
+	if separatorIndex := strings.Index(dependency.id, defaultDependencySeparator); separatorIndex >= 0 {
+		dependency.id, dependency.defaultValue = dependency.id[:separatorIndex], dependency.id[separatorIndex+1:]
+		dependency.hasDefault = true
+		isOptional = true
+	}
+
+	return dependency, isOptional && dependency.id != ""
+}
+
+//getOptional creates the optional dependency if it's declared or gives its default value otherwise, the existence is
+//checked before the resolution, so a missing service doesn't stay in the cycle detector
+func (r *resolution) getOptional(dependency optionalDependency, isCached bool) (interface{}, error) {
+	if r.isDeclared(dependency.id) {
+		return r.GetSecure(dependency.id, isCached)
+	}
+
+	if dependency.hasDefault {
+		return dependency.defaultValue, nil
+	}
+
+	return nil, nil
+}
+
+//isDeclared tells if the id refers to a service, an alias or tagged services in the scopes chain
+func (rc *RuntimeContainer) isDeclared(id string) bool {
+	if _, isTagReference := parseTagReference(id); isTagReference {
+		return true
+	}
+
+	if _, isAlias := rc.findAlias(id); isAlias {
+		return true
+	}
+
+	return rc.findOwner(id) != nil
+}
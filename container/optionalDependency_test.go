@@ -0,0 +1,146 @@
+package container
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+//tracedClient is a service with an optional tracer and a timeout with a default value
+type tracedClient struct {
+	tracer  *mocks.InMemoryLogger
+	retries int
+	timeout time.Duration
+}
+
+func newTracedClient(tracer *mocks.InMemoryLogger, retries int, timeout time.Duration) tracedClient {
+	return tracedClient{tracer: tracer, retries: retries, timeout: timeout}
+}
+
+func TestMissingOptionalDependencies(t *testing.T) {
+	cont := NewRuntimeContainer()
+	err := cont.AddNewMethod("client", newTracedClient, "?tracer", "?retries", "timeout|30s")
+	assertNoError(err, t)
+
+	AssertExpectedDependency(cont, "client", tracedClient{timeout: 30 * time.Second}, t)
+	assertNoError(cont.Check(), t)
+}
+
+func TestDeclaredOptionalDependencies(t *testing.T) {
+	tracer := &mocks.InMemoryLogger{}
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("client", newTracedClient, "?tracer", "?retries", "timeout|30s")
+	cont.AddNewMethod("tracer", func() *mocks.InMemoryLogger { return tracer })
+	RegisterParameters(cont, map[string]interface{}{"retries": "3", "timeout": "5s"})
+
+	AssertExpectedDependency(cont, "client", tracedClient{tracer: tracer, retries: 3, timeout: 5 * time.Second}, t)
+}
+
+func TestOptionalDependenciesInScope(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("client", newTracedClient, "?tracer", "?retries", "timeout|30s")
+	cont.SetLifetime("client", Scoped)
+
+	scope := cont.NewScope()
+	RegisterParameters(scope, map[string]interface{}{"retries": "2"})
+
+	AssertExpectedDependency(scope, "client", tracedClient{retries: 2, timeout: 30 * time.Second}, t)
+	AssertExpectedDependency(cont, "client", tracedClient{timeout: 30 * time.Second}, t)
+}
+
+func TestRepeatedMissingOptionalDependency(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("names", func(first string, rest ...string) string {
+		return strings.Join(append([]string{first}, rest...), ",")
+	}, "name|a", "?name", "name|c")
+
+	AssertExpectedDependency(cont, "names", "a,,c", t)
+
+	type optionalFields struct {
+		First  *mocks.InMemoryLogger `inject:"tracer,optional"`
+		Second *mocks.InMemoryLogger `inject:"tracer,optional"`
+		Size   int                   `inject:"page_size|50"`
+	}
+	cont.AddStruct("optional_fields", optionalFields{})
+	AssertExpectedDependency(cont, "optional_fields", optionalFields{Size: 50}, t)
+}
+
+func TestOptionalDependencyErrors(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("client", newTracedClient, "?tracer", "retries|many", "?timeout")
+	cont.AddNewMethod("timeout", func() (time.Duration, error) {
+		return 0, fmt.Errorf("Timeout is not configured")
+	})
+
+	_, err := cont.GetSecure("client", true)
+	assertErrorText(
+		"Cannot convert the value 'many' of the dependency 'retries|many' to 'int' in the Constr function call "+
+			"[check 'client' service];\n"+
+			"client -> timeout: Timeout is not configured [check 'timeout' service]",
+		err,
+		t,
+	)
+}
+
+func TestOptionalDependenciesValidation(t *testing.T) {
+	err := ValidateGraphSecure(Tree{
+		Node{ID: "client", NewFunc: newTracedClient, ServiceNames: Services{"?tracer", "?retries", "timeout|30s"}},
+	})
+	assertNoError(err, t)
+
+	tree := Tree{
+		Node{ID: "client", NewFunc: newTracedClient, ServiceNames: Services{"?tracer", "retries|many", "timeout|30s"}},
+		Node{ID: "tracer", NewFunc: mocks.NewInMemoryCache},
+	}
+	err = ValidateGraphSecure(tree)
+	assertErrorText(
+		"Cannot use the provided dependency 'tracer' of type '*mocks.InMemoryCache' as '*mocks.InMemoryLogger' "+
+			"in the Constr function call [check 'client' service];\n"+
+			"Cannot convert the value 'many' of the dependency 'retries|many' to 'int' in the Constr function call "+
+			"[check 'client' service]",
+		err,
+		t,
+	)
+
+	graph, err := ExportGraph(tree, DotFormat)
+	assertNoError(err, t)
+	if !strings.Contains(graph, `"client" -> "tracer";`) || strings.Contains(graph, "retries") {
+		t.Errorf("Only declared optional dependencies should be exported:\n%s", graph)
+	}
+}
+
+func TestOptionalDependencyReferencesCannotBeDeclared(t *testing.T) {
+	testCases := map[string]string{
+		"?tracer": "Cannot declare the service '?tracer' as the '?' prefix is reserved for optional dependencies",
+		"tracer|none": "Cannot declare the service 'tracer|none' as '|' is reserved for default values of " +
+			"optional dependencies",
+	}
+
+	for id, expectedError := range testCases {
+		cont := NewRuntimeContainer()
+
+		err := cont.AddConstructor(id, func(c Container) (interface{}, error) {
+			return nil, nil
+		})
+		assertErrorText(expectedError, err, t)
+
+		err = cont.AddNewMethod(id, mocks.NewFakeDb, "connection_string")
+		assertErrorText(expectedError, err, t)
+
+		err = cont.AddStruct(id, mocks.BookCreator{})
+		assertErrorText(expectedError, err, t)
+
+		err = cont.Alias(id, "tracer")
+		assertErrorText(expectedError, err, t)
+
+		func() {
+			defer ExpectPanic(t, expectedError)
+			cont.SetConstructor(id, func(c Container) (interface{}, error) {
+				return nil, nil
+			})
+		}()
+	}
+}
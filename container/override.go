@@ -0,0 +1,158 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+//Override replaces the service identified by id with the provided instance until the returned restore func is called,
+//it's meant for tests which swap a service with a fake. The replaced service and all cached services which depend on
+//it are removed from the cache, so they are created again with the fake. Decorators of the replaced service are not
+//applied to the fake. Services declared with AddConstructor hide their dependencies, so their cached instances are
+//always recreated. The restore func brings back the original declaration and the cached instances, scopes created
+//while the service is overridden keep their cached services. The frozen container can be overridden as well
+func (rc *RuntimeContainer) Override(id string, service interface{}) (restore func(), err error) {
+	if rc.findOwner(id) == nil {
+		return nil, fmt.Errorf("Cannot override the service '%s' as it is not declared in the container", id)
+	}
+
+	if serviceType, isTyped := rc.findServiceType(id); isTyped && service != nil {
+		if !reflect.TypeOf(service).AssignableTo(serviceType) {
+			return nil, &TypeMismatchError{ServiceID: id, Expected: serviceType, Provided: reflect.TypeOf(service)}
+		}
+	}
+
+	//dependents are found before locking the container as the lookup reads it
+	invalidatedIDs := append([]string{id}, rc.findDependents(id)...)
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	originalConstructor, hasConstructor := rc.constructors[id]
+	originalDecorators, isDecorated := rc.decorators[id]
+	originalServices := map[string]interface{}{}
+	originalCreationNumbers := map[string]uint64{}
+	for _, invalidatedID := range invalidatedIDs {
+		if cachedService, isCached := rc.cache.Get(invalidatedID); isCached {
+			originalServices[invalidatedID] = cachedService
+			originalCreationNumbers[invalidatedID] = rc.creationNumbers[invalidatedID]
+		}
+		rc.uncache(invalidatedID)
+	}
+
+	//constructors have priority over new methods, so the fake is given even by the compiled resolution plans
+	rc.constructors[id] = func(c Container) (interface{}, error) {
+		return service, nil
+	}
+	delete(rc.decorators, id)
+
+	once := sync.Once{}
+	restore = func() {
+		once.Do(func() {
+			rc.mutex.Lock()
+			defer rc.mutex.Unlock()
+
+			delete(rc.constructors, id)
+			if hasConstructor {
+				rc.constructors[id] = originalConstructor
+			}
+
+			if isDecorated {
+				rc.decorators[id] = originalDecorators
+			}
+
+			for _, invalidatedID := range invalidatedIDs {
+				rc.uncache(invalidatedID)
+				if originalService, wasCached := originalServices[invalidatedID]; wasCached {
+					rc.cache.Set(invalidatedID, originalService)
+					rc.creationNumbers[invalidatedID] = originalCreationNumbers[invalidatedID]
+				}
+			}
+		})
+	}
+
+	return restore, nil
+}
+
+//uncache removes the created service from the cache, must be called under the mutex
+func (rc *RuntimeContainer) uncache(id string) {
+	delete(rc.cache, id)
+	delete(rc.creationNumbers, id)
+}
+
+//findDependents gives ids of services which depend on the service identified by id directly or through other
+//services, aliases, tags and events
+func (rc *RuntimeContainer) findDependents(id string) []string {
+	descriptions := []ServiceDescription{}
+	providersByEvent := map[string][]string{}
+	servicesByTag := map[string][]string{}
+	for _, serviceID := range rc.Services() {
+		description, err := rc.Describe(serviceID)
+		if err != nil {
+			continue
+		}
+		descriptions = append(descriptions, description)
+
+		for _, event := range description.Events {
+			providersByEvent[event] = append(providersByEvent[event], serviceID)
+		}
+
+		for _, tag := range description.Tags {
+			servicesByTag[tag] = append(servicesByTag[tag], serviceID)
+		}
+	}
+
+	dependents := []string{}
+	isDependent := map[string]bool{id: true}
+	for hasNewDependents := true; hasNewDependents; {
+		hasNewDependents = false
+		for _, description := range descriptions {
+			if isDependent[description.ID] {
+				continue
+			}
+
+			if description.Kind == ConstructorRegistration ||
+				rc.dependsOnAny(description, isDependent, providersByEvent, servicesByTag) {
+				dependents = append(dependents, description.ID)
+				isDependent[description.ID] = true
+				hasNewDependents = true
+			}
+		}
+	}
+
+	return dependents
+}
+
+//dependsOnAny tells if one of the service dependencies or dependencies provided to it by events is in the set of ids
+func (rc *RuntimeContainer) dependsOnAny(
+	description ServiceDescription,
+	ids map[string]bool,
+	providersByEvent map[string][]string,
+	servicesByTag map[string][]string,
+) bool {
+	dependencyIDs := []string{}
+	for _, dependencyName := range description.Dependencies {
+		if optional, isOptional := parseOptionalDependency(dependencyName); isOptional {
+			dependencyName = optional.id
+		}
+
+		if tag, isTagReference := parseTagReference(dependencyName); isTagReference {
+			dependencyIDs = append(dependencyIDs, servicesByTag[tag]...)
+			continue
+		}
+		dependencyIDs = append(dependencyIDs, dependencyName)
+	}
+
+	for _, event := range description.ObservedEvents {
+		dependencyIDs = append(dependencyIDs, providersByEvent[event]...)
+	}
+
+	for _, dependencyID := range dependencyIDs {
+		if ids[dependencyID] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,100 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func createContainerForOverride() *RuntimeContainer {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func() *mocks.FakeDb { return &mocks.FakeDb{} })
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	cont.Alias("storage", "book_storage")
+	cont.AddNewMethod("authors_storage", mocks.NewAuthorsStorage, "db")
+	cont.AddTag("authors_storage", "stats_provider")
+	cont.AddNewMethod("stats_providers", func(providers ...mocks.StatisticsProvider) int {
+		return len(providers)
+	}, "#stats_provider")
+	cont.AddNewMethod("statistics_gateway", mocks.NewStatisticsGateway)
+	cont.AddDependencyObserver("statistics_provider", "statistics_gateway", func(sg *mocks.StatisticsGateway, sp mocks.StatisticsProvider) {
+		sg.AddStatisticsProvider(sp)
+	})
+	cont.RegisterDependencyEvent("statistics_provider", "storage")
+	cont.AddNewMethod("cache", mocks.NewInMemoryCache)
+
+	return cont
+}
+
+func TestOverride(t *testing.T) {
+	cont := createContainerForOverride()
+	cont.AddConstructor("config", func(c Container) (interface{}, error) {
+		return mocks.NewConfig(), nil
+	})
+	for _, id := range []string{"storage", "stats_providers", "statistics_gateway", "cache", "config"} {
+		cont.Get(id, true)
+	}
+	originalDb := cont.Get("db", true)
+	originalStorage := cont.Get("book_storage", true)
+	originalGateway := cont.Get("statistics_gateway", true)
+	originalCache := cont.Get("cache", true)
+
+	fakeDb := &mocks.FakeDb{}
+	restore, err := cont.Override("db", fakeDb)
+	assertNoError(err, t)
+	assertIDs("cache", cont.Instantiated(), t)
+
+	if cont.Get("db", true) != fakeDb || cont.Get("storage", true) != mocks.NewBookStorage(fakeDb) {
+		t.Error("The fake and services created with it are expected")
+	}
+	if cont.Get("statistics_gateway", true) == originalGateway {
+		t.Error("The observer of the service depending on the fake should be created again")
+	}
+	if cont.Get("cache", true) != originalCache {
+		t.Error("Services which don't depend on the fake should be taken from the cache")
+	}
+
+	restore()
+	restore()
+	assertIDs("authors_storage,book_storage,cache,config,db,statistics_gateway,stats_providers", cont.Instantiated(), t)
+	if cont.Get("db", true) != originalDb || cont.Get("storage", true) != originalStorage {
+		t.Error("Original cached services should be restored")
+	}
+	if cont.Get("db", false) == originalDb || cont.Get("db", false) == fakeDb {
+		t.Error("The original declaration should be restored")
+	}
+}
+
+func TestOverrideOfDecoratedService(t *testing.T) {
+	cont := createContainerForOverride()
+	cont.Decorate("cache", func(cache *mocks.InMemoryCache) *mocks.InMemoryCache {
+		return &mocks.InMemoryCache{}
+	})
+	cont.Freeze()
+
+	fakeCache := mocks.NewInMemoryCache()
+	restore, err := cont.Override("cache", fakeCache)
+	assertNoError(err, t)
+	if cont.Get("cache", true) != fakeCache {
+		t.Error("The fake should not be decorated")
+	}
+
+	restore()
+	if cont.Get("cache", true) == fakeCache {
+		t.Error("The original decorated service should be restored")
+	}
+}
+
+func TestOverrideErrors(t *testing.T) {
+	cont := createContainerForOverride()
+
+	_, err := cont.Override("unknown_db", &mocks.FakeDb{})
+	assertErrorText("Cannot override the service 'unknown_db' as it is not declared in the container", err, t)
+
+	_, err = cont.Override("db", mocks.NewInMemoryCache())
+	assertErrorText(
+		"Cannot use the service 'db' of type '*mocks.InMemoryCache' as '*mocks.FakeDb' [check 'db' service]",
+		err,
+		t,
+	)
+}
@@ -0,0 +1,208 @@
+package container
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+//workerPool limits the number of goroutines which create services in parallel
+type workerPool struct {
+	slots chan struct{}
+}
+
+func newWorkerPool(workersCount int) *workerPool {
+	return &workerPool{slots: make(chan struct{}, workersCount)}
+}
+
+func (wp *workerPool) acquire() {
+	wp.slots <- struct{}{}
+}
+
+//tryAcquire takes a free worker if there is one, nested parallel resolutions don't wait for workers but continue in
+//the current goroutine, so the pool can never be exhausted by goroutines waiting for each other
+func (wp *workerPool) tryAcquire() bool {
+	if wp == nil {
+		return false
+	}
+
+	select {
+	case wp.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (wp *workerPool) release() {
+	<-wp.slots
+}
+
+//SetParallelism enables resolution of sibling dependencies of a service in parallel with at most workersCount
+//additional goroutines, workersCount less than 2 switches back to the sequential resolution. The setting is shared by
+//all scopes of the container
+func (rc *RuntimeContainer) SetParallelism(workersCount int) {
+	root := rc.getRoot()
+
+	root.mutex.Lock()
+	defer root.mutex.Unlock()
+
+	if workersCount < 2 {
+		root.workerPool = nil
+		return
+	}
+	root.workerPool = newWorkerPool(workersCount)
+}
+
+//WarmUp creates and caches the services identified by ids in parallel or all declared services if no ids are provided,
+//it uses the pool configured by SetParallelism or a pool of GOMAXPROCS workers otherwise
+func (rc *RuntimeContainer) WarmUp(ids ...string) error {
+	if len(ids) == 0 {
+		ids = rc.getServiceIDs()
+		sort.Strings(ids)
+	}
+
+	pool := rc.getWorkerPool()
+	if pool == nil {
+		pool = newWorkerPool(runtime.GOMAXPROCS(0))
+	}
+
+	errs := make([]error, len(ids))
+	wg := sync.WaitGroup{}
+	wg.Add(len(ids))
+	for i, id := range ids {
+		pool.acquire()
+		go func(i int, id string) {
+			defer wg.Done()
+			defer pool.release()
+
+			_, errs[i] = rc.GetSecure(id, true)
+		}(i, id)
+	}
+	wg.Wait()
+
+	return mergeErrors(errs)
+}
+
+func (rc *RuntimeContainer) getRoot() *RuntimeContainer {
+	root := rc
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	return root
+}
+
+func (rc *RuntimeContainer) getWorkerPool() *workerPool {
+	root := rc.getRoot()
+
+	root.mutex.RLock()
+	defer root.mutex.RUnlock()
+
+	return root.workerPool
+}
+
+//dependenciesResolver gives the dependency of a New method by the index of its argument name
+type dependenciesResolver func(i int) (interface{}, error)
+
+//newDependenciesResolver fetches all dependencies at once in parallel if it's enabled for the container,
+//otherwise every dependency is fetched on demand
+func newDependenciesResolver(c Container, dependencyNames []string, isCached bool) dependenciesResolver {
+	r, isResolution := c.(*resolution)
+	if isResolution && len(dependencyNames) > 1 {
+		if pool := r.getWorkerPool(); pool != nil {
+			dependencies, errs := r.getInParallel(pool, dependencyNames, isCached)
+			return func(i int) (interface{}, error) {
+				return dependencies[i], errs[i]
+			}
+		}
+	}
+
+	return func(i int) (interface{}, error) {
+		return c.GetSecure(dependencyNames[i], isCached)
+	}
+}
+
+//getInParallel fetches services in branches of the current resolution, every branch runs in a separate goroutine
+//if there is a free worker in the pool or in the current goroutine otherwise. A panic in a branch is repeated in the
+//current goroutine after all branches are finished
+func (r *resolution) getInParallel(pool *workerPool, ids []string, isCached bool) ([]interface{}, []error) {
+	services := make([]interface{}, len(ids))
+	errs := make([]error, len(ids))
+	panics := make([]interface{}, len(ids))
+
+	branches := make([]*resolution, len(ids))
+	for i := range ids {
+		branches[i] = r.fork()
+	}
+
+	wg := sync.WaitGroup{}
+	for i, id := range ids {
+		//the last branch is resolved in the current goroutine as it would wait for the others anyway
+		if i < len(ids)-1 && pool.tryAcquire() {
+			wg.Add(1)
+			go func(i int, id string) {
+				defer wg.Done()
+				defer pool.release()
+				defer func() {
+					panics[i] = recover()
+				}()
+
+				services[i], errs[i] = branches[i].GetSecure(id, isCached)
+			}(i, id)
+			continue
+		}
+
+		func() {
+			defer func() {
+				panics[i] = recover()
+			}()
+
+			services[i], errs[i] = branches[i].GetSecure(id, isCached)
+		}()
+	}
+	wg.Wait()
+
+	for _, branch := range branches {
+		r.join(branch)
+	}
+
+	for _, p := range panics {
+		if p != nil {
+			panic(p)
+		}
+	}
+
+	return services, errs
+}
+
+//fork creates a branch of the resolution with its own copy of the cycle detector, so branches can run in parallel
+func (r *resolution) fork() *resolution {
+	r.waitsMutex.Lock()
+	defer r.waitsMutex.Unlock()
+
+	branchState := &resolutionState{
+		ctx:           r.ctx,
+		cycleDetector: r.cycleDetector.fork(),
+		origin:        r.origin,
+		parent:        r.resolutionState,
+	}
+	if r.branches == nil {
+		r.branches = map[*resolutionState]bool{}
+	}
+	r.branches[branchState] = true
+
+	return &resolution{
+		RuntimeContainer: r.RuntimeContainer,
+		resolutionState:  branchState,
+		path:             r.path,
+	}
+}
+
+//join forgets a finished branch of the resolution
+func (r *resolution) join(branch *resolution) {
+	r.waitsMutex.Lock()
+	defer r.waitsMutex.Unlock()
+
+	delete(r.branches, branch.resolutionState)
+}
@@ -0,0 +1,171 @@
+package container
+
+import (
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+//startBarrier lets constructors finish only when all of them are running at the same time
+type startBarrier struct {
+	expectedCount int32
+	startedCount  int32
+	allStarted    chan bool
+}
+
+func newStartBarrier(expectedCount int32) *startBarrier {
+	return &startBarrier{expectedCount: expectedCount, allStarted: make(chan bool)}
+}
+
+func (sb *startBarrier) wait() error {
+	if atomic.AddInt32(&sb.startedCount, 1) == sb.expectedCount {
+		close(sb.allStarted)
+	}
+
+	select {
+	case <-sb.allStarted:
+		return nil
+	case <-time.After(time.Second):
+		return errors.New("Constructors were not called in parallel")
+	}
+}
+
+func createContainerWithSlowServices(barrier *startBarrier) *RuntimeContainer {
+	cont := NewRuntimeContainer()
+	newSlowDb := func() (*mocks.FakeDb, error) {
+		return mocks.NewFakeDb("someConnectionString"), barrier.wait()
+	}
+	cont.AddNewMethod("db", newSlowDb)
+	cont.AddNewMethod("statistics_db", newSlowDb)
+	cont.AddNewMethod("cache_db", newSlowDb)
+	cont.AddNewMethod(
+		"application",
+		func(db, statisticsDb, cacheDb *mocks.FakeDb) []*mocks.FakeDb {
+			return []*mocks.FakeDb{db, statisticsDb, cacheDb}
+		},
+		"db",
+		"statistics_db",
+		"cache_db",
+	)
+
+	return cont
+}
+
+func TestParallelResolutionOfSiblingDependencies(t *testing.T) {
+	cont := createContainerWithSlowServices(newStartBarrier(3))
+	cont.SetParallelism(2)
+
+	_, err := cont.GetSecure("application", true)
+	assertNoError(err, t)
+}
+
+func TestDisabledParallelism(t *testing.T) {
+	cont := NewRuntimeContainer()
+	scope := cont.NewScope()
+
+	scope.SetParallelism(4)
+	if cont.getWorkerPool() == nil {
+		t.Error("Parallelism of a scope should be shared with its parent")
+	}
+
+	cont.SetParallelism(1)
+	if scope.getWorkerPool() != nil {
+		t.Error("Parallelism should be disabled for less than 2 workers")
+	}
+}
+
+func TestParallelResolutionCreatesSharedDependencyOnce(t *testing.T) {
+	var dbCreationsCount int32
+	cont := NewRuntimeContainer()
+	cont.SetParallelism(8)
+	cont.AddNewMethod("db", func() *mocks.FakeDb {
+		atomic.AddInt32(&dbCreationsCount, 1)
+		time.Sleep(time.Millisecond * 10)
+		return mocks.NewFakeDb("someConnectionString")
+	})
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	cont.AddNewMethod("authors_storage", mocks.NewAuthorsStorage, "db")
+	cont.AddNewMethod(
+		"storages",
+		func(db *mocks.FakeDb, bs mocks.BookStorage, as mocks.AuthorsStorage) bool { return true },
+		"db",
+		"book_storage",
+		"authors_storage",
+	)
+
+	scope := cont.NewScope()
+	runConcurrently(goroutinesCount, func(i int) {
+		_, err := scope.GetSecure("storages", true)
+		assertNoError(err, t)
+	})
+
+	if dbCreationsCount != 1 {
+		t.Errorf("The service 'db' should be created once, but it was created %d times", dbCreationsCount)
+	}
+}
+
+func TestCycleDetectionInParallelBranches(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.SetParallelism(4)
+	cont.AddNewMethod("user_name", func(roleName, rightName string) string { return roleName }, "role_name", "right_name")
+	cont.AddNewMethod("role_name", func() string { return "admin" })
+	cont.AddNewMethod("right_name", func(userName string) string { return userName }, "user_name")
+
+	_, err := cont.GetSecure("user_name", true)
+
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("CycleError is expected in '%v'", err)
+	}
+
+	if !reflect.DeepEqual(cycleErr.Path, []string{"user_name", "right_name", "user_name"}) {
+		t.Errorf("Unexpected cycle path %v", cycleErr.Path)
+	}
+
+	cont = CreateContainer()
+	cont.SetParallelism(4)
+	for _, id := range []string{"book_finder", "book_link_provider", "book_downloader", "statistics_gateway"} {
+		_, err = cont.GetSecure(id, false)
+		assertNoError(err, t)
+	}
+}
+
+func TestPanicInParallelBranch(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.SetParallelism(4)
+	cont.AddNewMethod("role_name", func() string { panic("Roles are not loaded") })
+	cont.AddNewMethod("right_name", func() string { return "read" })
+	cont.AddNewMethod("user_name", func(roleName, rightName string) string { return roleName }, "role_name", "right_name")
+
+	defer ExpectPanic(t, "Roles are not loaded")
+	cont.Get("user_name", true)
+}
+
+func TestWarmUp(t *testing.T) {
+	cont := createContainerWithSlowServices(newStartBarrier(3))
+	cont.SetParallelism(3)
+
+	err := cont.WarmUp("db", "statistics_db", "cache_db")
+	assertNoError(err, t)
+
+	for _, id := range []string{"db", "statistics_db", "cache_db"} {
+		if _, isCached := cont.cache.Get(id); !isCached {
+			t.Errorf("The service '%s' should be cached after the warm up", id)
+		}
+	}
+
+	cont = CreateContainer()
+	err = cont.WarmUp()
+	assertNoError(err, t)
+
+	if _, isCached := cont.cache.Get("book_finder"); !isCached {
+		t.Error("All services should be cached after the warm up")
+	}
+
+	err = cont.WarmUp("book_finder", "unknown_service")
+	assertErrorText("Unknown dependency 'unknown_service'", err, t)
+}
@@ -0,0 +1,244 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//placeholderRegexp matches an escaped percent sign "%%", a parameter placeholder "%param.name%" and an environment
+//variable placeholder "${ENV_NAME}" or "${ENV_NAME:-default value}"
+var placeholderRegexp = regexp.MustCompile(`%%|%([\w.\-]+)%|\$\{([A-Za-z_]\w*)(:-([^}]*))?\}`)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+//parametersContainer knows which services are parameters, so it converts only string parameters to arguments
+//of other types and lets placeholders refer only to parameters
+type parametersContainer interface {
+	addParameter(name string, value interface{})
+	findParameter(name string) (interface{}, bool)
+}
+
+//parametersResolver replaces placeholders in string parameters with values of other parameters and environment variables
+type parametersResolver struct {
+	container  Container
+	parameters map[string]interface{}
+	resolved   map[string]interface{}
+	failures   map[string]error
+	stack      []string
+}
+
+func newParametersResolver(c Container, parameters map[string]interface{}) *parametersResolver {
+	return &parametersResolver{
+		container:  c,
+		parameters: parameters,
+		resolved:   map[string]interface{}{},
+		failures:   map[string]error{},
+		stack:      []string{},
+	}
+}
+
+//resolve gives the value of a parameter with all placeholders replaced, a value consisting of a single parameter
+//placeholder, e.g. "%db.port%", keeps the type of the referenced parameter
+func (pr *parametersResolver) resolve(name string) (interface{}, error) {
+	if value, isResolved := pr.resolved[name]; isResolved {
+		return value, nil
+	}
+
+	if err, isFailed := pr.failures[name]; isFailed {
+		return nil, err
+	}
+
+	for i, stackName := range pr.stack {
+		if stackName == name {
+			cycle := append(append([]string{}, pr.stack[i:]...), name)
+			return nil, fmt.Errorf("Detected parameters' cycle: %s", strings.Join(cycle, "->"))
+		}
+	}
+
+	value := pr.parameters[name]
+	stringValue, isString := value.(string)
+	if !isString {
+		pr.resolved[name] = value
+		return value, nil
+	}
+
+	pr.stack = append(pr.stack, name)
+	value, err := pr.interpolate(name, stringValue)
+	pr.stack = pr.stack[:len(pr.stack)-1]
+	if err != nil {
+		pr.failures[name] = err
+		return nil, err
+	}
+
+	pr.resolved[name] = value
+	return value, nil
+}
+
+func (pr *parametersResolver) interpolate(name, value string) (interface{}, error) {
+	matches := placeholderRegexp.FindAllStringSubmatchIndex(value, -1)
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(value) && matches[0][2] >= 0 {
+		return pr.resolveReference(name, value[matches[0][2]:matches[0][3]])
+	}
+
+	result := strings.Builder{}
+	lastIndex := 0
+	for _, match := range matches {
+		result.WriteString(value[lastIndex:match[0]])
+		lastIndex = match[1]
+
+		switch {
+		case match[2] >= 0:
+			reference, err := pr.resolveReference(name, value[match[2]:match[3]])
+			if err != nil {
+				return nil, err
+			}
+			result.WriteString(fmt.Sprint(reference))
+		case match[4] >= 0:
+			envName := value[match[4]:match[5]]
+			envValue, isSet := os.LookupEnv(envName)
+			if match[6] >= 0 && envValue == "" {
+				envValue, isSet = value[match[8]:match[9]], true
+			}
+			if !isSet {
+				return nil, fmt.Errorf("Environment variable '%s' is not set [check '%s' parameter]", envName, name)
+			}
+			result.WriteString(envValue)
+		default:
+			result.WriteString("%")
+		}
+	}
+	result.WriteString(value[lastIndex:])
+
+	return result.String(), nil
+}
+
+//resolveReference gives the value of a referenced parameter declared together with the current one or registered
+//in the container before, other services are never referenced, so placeholders don't create them
+func (pr *parametersResolver) resolveReference(name, referenceName string) (interface{}, error) {
+	if _, exists := pr.parameters[referenceName]; exists {
+		return pr.resolve(referenceName)
+	}
+
+	if registeredParameters, isParametersContainer := pr.container.(parametersContainer); isParametersContainer {
+		if value, isParameter := registeredParameters.findParameter(referenceName); isParameter {
+			return value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unknown parameter '%s' is referenced [check '%s' parameter]", referenceName, name)
+}
+
+//addParameter remembers the value of the parameter registered in the container
+func (rc *RuntimeContainer) addParameter(name string, value interface{}) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	rc.parameters[name] = value
+}
+
+//findParameter gives the value of the parameter registered in the scopes chain, a service declared in a scope
+//with the id of a parameter of the parent container hides it
+func (rc *RuntimeContainer) findParameter(name string) (interface{}, bool) {
+	owner := rc.findOwner(name)
+	if owner == nil {
+		return nil, false
+	}
+
+	owner.mutex.RLock()
+	defer owner.mutex.RUnlock()
+
+	value, isParameter := owner.parameters[name]
+
+	return value, isParameter
+}
+
+//isParameterDependency checks if the dependency is a parameter or the default value of a missing optional dependency,
+//only such dependencies are converted from strings to arguments of other types
+func isParameterDependency(c Container, dependencyName string) bool {
+	if optional, isOptional := parseOptionalDependency(dependencyName); isOptional {
+		if optional.hasDefault && !c.Exists(optional.id) {
+			return true
+		}
+		dependencyName = optional.id
+	}
+
+	registeredParameters, isParametersContainer := c.(parametersContainer)
+	if !isParametersContainer {
+		return false
+	}
+	_, isParameter := registeredParameters.findParameter(dependencyName)
+
+	return isParameter
+}
+
+//isConvertibleFromString checks if a string parameter can be converted to the provided type
+func isConvertibleFromString(reflectedType reflect.Type) bool {
+	switch reflectedType.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+//convertStringDependency converts a string dependency, e.g. a parameter taken from an environment variable,
+//to a bool, number or time.Duration argument, other dependencies are returned as they are
+func convertStringDependency(
+	expectedDependency reflect.Type,
+	reflectedDependency reflect.Value,
+	dependencyName,
+	serviceId string,
+) (reflect.Value, error) {
+	if !reflectedDependency.IsValid() ||
+		reflectedDependency.Kind() != reflect.String ||
+		reflectedDependency.Type().AssignableTo(expectedDependency) ||
+		!isConvertibleFromString(expectedDependency) {
+		return reflectedDependency, nil
+	}
+
+	stringValue := strings.TrimSpace(reflectedDependency.String())
+	convertedValue := reflect.New(expectedDependency).Elem()
+	var err error
+	switch kind := expectedDependency.Kind(); {
+	case expectedDependency == durationType:
+		var duration time.Duration
+		duration, err = time.ParseDuration(stringValue)
+		convertedValue.SetInt(int64(duration))
+	case kind == reflect.Bool:
+		var boolValue bool
+		boolValue, err = strconv.ParseBool(stringValue)
+		convertedValue.SetBool(boolValue)
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		var intValue int64
+		intValue, err = strconv.ParseInt(stringValue, 10, expectedDependency.Bits())
+		convertedValue.SetInt(intValue)
+	case kind >= reflect.Uint && kind <= reflect.Uint64:
+		var uintValue uint64
+		uintValue, err = strconv.ParseUint(stringValue, 10, expectedDependency.Bits())
+		convertedValue.SetUint(uintValue)
+	default:
+		var floatValue float64
+		floatValue, err = strconv.ParseFloat(stringValue, expectedDependency.Bits())
+		convertedValue.SetFloat(floatValue)
+	}
+
+	if err != nil {
+		return reflectedDependency, fmt.Errorf(
+			"Cannot convert the value '%s' of the dependency '%s' to '%s' in the Constr function call [check '%s' service]",
+			stringValue,
+			dependencyName,
+			expectedDependency,
+			serviceId,
+		)
+	}
+
+	return convertedValue, nil
+}
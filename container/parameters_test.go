@@ -0,0 +1,154 @@
+package container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func TestParametersPlaceholders(t *testing.T) {
+	t.Setenv("GOTAINER_DB_HOST", "db.local")
+	t.Setenv("GOTAINER_EMPTY", "")
+
+	c := NewRuntimeContainer()
+	err := RegisterParameters(c, map[string]interface{}{"db.user": "root"})
+	assertNoError(err, t)
+
+	err = RegisterParameters(c, map[string]interface{}{
+		"dsn":           "postgres://%db.user%@%db.host%:%db.port%/app",
+		"db.host":       "${GOTAINER_DB_HOST}",
+		"db.port":       5432,
+		"port":          "%db.port%",
+		"http_port":     "${GOTAINER_HTTP_PORT:-8080}",
+		"empty_default": "${GOTAINER_EMPTY:-default}",
+		"discount":      "100%% of %db.user%",
+		"log_format":    "%s - %d",
+	})
+	assertNoError(err, t)
+
+	AssertExpectedDependency(c, "dsn", "postgres://root@db.local:5432/app", t)
+	AssertExpectedDependency(c, "port", 5432, t)
+	AssertExpectedDependency(c, "http_port", "8080", t)
+	AssertExpectedDependency(c, "empty_default", "default", t)
+	AssertExpectedDependency(c, "discount", "100% of root", t)
+	AssertExpectedDependency(c, "log_format", "%s - %d", t)
+}
+
+func TestParametersPlaceholdersInConfig(t *testing.T) {
+	tree := Tree{
+		Node{Parameters: map[string]interface{}{"connection_string": "%db.driver%://%EnableLogging%"}},
+		Node{ID: "db", NewFunc: mocks.NewFakeDb, ServiceNames: Services{"connection_string"}},
+		Node{Parameters: map[string]interface{}{"db.driver": "mysql"}, ParamProvider: mocks.ConfigProvider{}},
+	}
+
+	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfigSecure(tree)
+	assertNoError(err, t)
+
+	AssertExpectedDependency(cont, "connection_string", "mysql://true", t)
+}
+
+func TestParametersPlaceholdersErrors(t *testing.T) {
+	c := NewRuntimeContainer()
+	err := RegisterParameters(c, map[string]interface{}{"dsn": "postgres://%db.user%@localhost"})
+	assertErrorText("Unknown parameter 'db.user' is referenced [check 'dsn' parameter]", err, t)
+
+	err = RegisterParameters(c, map[string]interface{}{"port": "${GOTAINER_NOT_EXISTING_PORT}"})
+	assertErrorText("Environment variable 'GOTAINER_NOT_EXISTING_PORT' is not set [check 'port' parameter]", err, t)
+
+	if c.Exists("dsn") || c.Exists("port") {
+		t.Error("Parameters with unresolved placeholders should not be registered")
+	}
+}
+
+func TestParametersCycles(t *testing.T) {
+	c := NewRuntimeContainer()
+	err := RegisterParameters(c, map[string]string{"a": "%b%"}, map[string]string{"b": "x%c%", "c": "%a%"})
+	assertErrorText("Detected parameters' cycle: a->b->c->a", err, t)
+
+	err = RegisterParameters(c, map[string]string{"self": "%self%"})
+	assertErrorText("Detected parameters' cycle: self->self", err, t)
+}
+
+func TestStringParametersConversion(t *testing.T) {
+	t.Setenv("GOTAINER_HTTP_PORT", "9090")
+
+	type server struct {
+		port      int
+		timeout   time.Duration
+		isSecure  bool
+		rateLimit float64
+	}
+
+	c := NewRuntimeContainer()
+	err := RegisterParameters(c, map[string]interface{}{
+		"port":       "${GOTAINER_HTTP_PORT:-8080}",
+		"timeout":    "1m30s",
+		"is_secure":  "true",
+		"rate_limit": "0.5",
+		"wrong_port": "http",
+	})
+	assertNoError(err, t)
+
+	newServer := func(port int, timeout time.Duration, isSecure bool, rateLimit float64) server {
+		return server{port: port, timeout: timeout, isSecure: isSecure, rateLimit: rateLimit}
+	}
+
+	err = c.AddNewMethod("server", newServer, "port", "timeout", "is_secure", "rate_limit")
+	assertNoError(err, t)
+
+	expectedServer := server{port: 9090, timeout: 90 * time.Second, isSecure: true, rateLimit: 0.5}
+	AssertExpectedDependency(c, "server", expectedServer, t)
+
+	err = c.AddNewMethod("wrong_server", newServer, "wrong_port", "timeout", "is_secure", "rate_limit")
+	assertNoError(err, t)
+
+	_, err = c.GetSecure("wrong_server", true)
+	assertErrorText(
+		"Cannot convert the value 'http' of the dependency 'wrong_port' to 'int' in the Constr function call [check 'wrong_server' service]",
+		err,
+		t,
+	)
+}
+
+func TestStringServicesAreNotConverted(t *testing.T) {
+	c := NewRuntimeContainer()
+	err := c.AddNewMethod("port", func() string { return "8080" })
+	assertNoError(err, t)
+
+	err = c.AddNewMethod("port_number", func(port int) int { return port }, "port")
+	assertNoError(err, t)
+
+	_, err = c.GetSecure("port_number", true)
+	assertErrorText(
+		"Cannot use the provided dependency 'port' of type 'string' as 'int' in the Constr function call [check 'port_number' service]",
+		err,
+		t,
+	)
+}
+
+func TestParametersPlaceholdersDoNotReferToServices(t *testing.T) {
+	isCreated := false
+	c := NewRuntimeContainer()
+	err := c.AddConstructor("db.user", func(c Container) (interface{}, error) {
+		isCreated = true
+		return "root", nil
+	})
+	assertNoError(err, t)
+
+	err = RegisterParameters(c, map[string]interface{}{"dsn": "postgres://%db.user%@localhost"})
+	assertErrorText("Unknown parameter 'db.user' is referenced [check 'dsn' parameter]", err, t)
+	if isCreated {
+		t.Error("A service should not be created for a parameter placeholder")
+	}
+}
+
+func TestGraphValidationOfStringParameters(t *testing.T) {
+	tree := Tree{
+		Node{Parameters: map[string]interface{}{"port": "${HTTP_PORT:-8080}"}},
+		Node{ID: "port_number", NewFunc: func(port uint16) uint16 { return port }, ServiceNames: Services{"port"}},
+	}
+
+	err := ValidateGraphSecure(tree)
+	assertNoError(err, t)
+}
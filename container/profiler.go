@@ -0,0 +1,214 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+//ProfileNode is a service created during the profiling with the services created for it
+type ProfileNode struct {
+	ID string `json:"id"`
+	//OwnDuration is the construction time of the service without the creation of its dependencies
+	OwnDuration time.Duration `json:"own_duration"`
+	//TotalDuration is the construction time of the service including the creation of its dependencies
+	TotalDuration time.Duration `json:"total_duration"`
+	//IsSlow tells that the own construction time of the service is above the threshold of the profiler
+	IsSlow       bool           `json:"is_slow"`
+	Error        string         `json:"error,omitempty"`
+	Dependencies []*ProfileNode `json:"dependencies,omitempty"`
+}
+
+//ProfileReport is a tree of services created during the profiling, roots are services requested from the container
+//directly and dependencies are services created for them
+type ProfileReport struct {
+	Threshold time.Duration  `json:"threshold"`
+	Services  []*ProfileNode `json:"services"`
+}
+
+//Profiler is a resolution hook which measures the construction time of every created service, services taken
+//from the cache are skipped. Add it to the container before the startup or the Check call and get the Profile after it
+type Profiler struct {
+	threshold time.Duration
+	roots     []*ProfileNode
+	//unfinishedNodes are services which dependencies are already created while they are still under construction
+	unfinishedNodes map[profileNodeKey]*ProfileNode
+	mutex           sync.Mutex
+}
+
+//profileNodeKey identifies a service under construction by its resolution path in the top level request
+type profileNodeKey struct {
+	request *resolutionState
+	path    string
+}
+
+//NewProfiler creates a profiler which flags services constructed longer than the threshold, a zero threshold disables
+//flagging
+func NewProfiler(threshold time.Duration) *Profiler {
+	return &Profiler{
+		threshold:       threshold,
+		roots:           []*ProfileNode{},
+		unfinishedNodes: map[profileNodeKey]*ProfileNode{},
+	}
+}
+
+//OnResolveStart does nothing as services are added to the profile when they are created
+func (p *Profiler) OnResolveStart(id string) {
+}
+
+//OnResolveEnd does nothing as the profiler needs the resolution path of the service
+func (p *Profiler) OnResolveEnd(id string, duration time.Duration, err error, fromCache bool) {
+}
+
+//OnEventDispatched does nothing as dependencies of observers are profiled as their usual dependencies
+func (p *Profiler) OnEventDispatched(event, observer, dependency string) {
+}
+
+//onResolvePathEnd adds the created service to its parent in the resolution path, the parent is still under
+//construction as dependencies are always created before the service
+func (p *Profiler) onResolvePathEnd(
+	request *resolutionState,
+	path []string,
+	duration time.Duration,
+	err error,
+	fromCache bool,
+) {
+	if fromCache || len(path) == 0 {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	pathKey := profileNodeKey{request: request, path: strings.Join(path, "\x00")}
+	node, isUnfinished := p.unfinishedNodes[pathKey]
+	if isUnfinished {
+		delete(p.unfinishedNodes, pathKey)
+	} else {
+		node = &ProfileNode{ID: path[len(path)-1]}
+	}
+
+	node.TotalDuration = duration
+	node.OwnDuration = duration
+	for _, dependency := range node.Dependencies {
+		node.OwnDuration -= dependency.TotalDuration
+	}
+	//dependencies created in parallel take less time than the sum of their durations
+	if node.OwnDuration < 0 {
+		node.OwnDuration = 0
+	}
+	node.IsSlow = p.threshold > 0 && node.OwnDuration > p.threshold
+	if err != nil {
+		node.Error = err.Error()
+	}
+
+	if len(path) == 1 {
+		p.roots = append(p.roots, node)
+		return
+	}
+
+	parentKey := profileNodeKey{request: request, path: strings.Join(path[:len(path)-1], "\x00")}
+	parent, isParentUnfinished := p.unfinishedNodes[parentKey]
+	if !isParentUnfinished {
+		parent = &ProfileNode{ID: path[len(path)-2]}
+		p.unfinishedNodes[parentKey] = parent
+	}
+	parent.Dependencies = append(parent.Dependencies, node)
+}
+
+//Profile gives the report of services created since the profiler was added to the container or was reset
+func (p *Profiler) Profile() *ProfileReport {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	report := &ProfileReport{Threshold: p.threshold, Services: make([]*ProfileNode, 0, len(p.roots))}
+	for _, root := range p.roots {
+		report.Services = append(report.Services, root.copy())
+	}
+
+	return report
+}
+
+//Reset removes all profiled services
+func (p *Profiler) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.roots = []*ProfileNode{}
+	p.unfinishedNodes = map[profileNodeKey]*ProfileNode{}
+}
+
+func (pn *ProfileNode) copy() *ProfileNode {
+	nodeCopy := *pn
+	nodeCopy.Dependencies = nil
+	for _, dependency := range pn.Dependencies {
+		nodeCopy.Dependencies = append(nodeCopy.Dependencies, dependency.copy())
+	}
+
+	return &nodeCopy
+}
+
+//Text gives the tree of created services with an indented line per service, e.g.
+//books_handler total=12ms own=2ms
+//  db total=10ms own=10ms SLOW
+func (pr *ProfileReport) Text() string {
+	builder := &strings.Builder{}
+	var writeNode func(node *ProfileNode, depth int)
+	writeNode = func(node *ProfileNode, depth int) {
+		builder.WriteString(fmt.Sprintf(
+			"%s%s total=%s own=%s",
+			strings.Repeat("  ", depth),
+			node.ID,
+			node.TotalDuration,
+			node.OwnDuration,
+		))
+		if node.IsSlow {
+			builder.WriteString(" SLOW")
+		}
+		if node.Error != "" {
+			builder.WriteString(fmt.Sprintf(" error=%q", node.Error))
+		}
+		builder.WriteString("\n")
+
+		for _, dependency := range node.Dependencies {
+			writeNode(dependency, depth+1)
+		}
+	}
+
+	for _, service := range pr.Services {
+		writeNode(service, 0)
+	}
+
+	return builder.String()
+}
+
+//JSON gives the tree of created services in the JSON format, durations are given in nanoseconds
+func (pr *ProfileReport) JSON() ([]byte, error) {
+	return json.Marshal(pr)
+}
+
+//Folded gives own construction times of services in microseconds in the folded stacks format of flame graph tools,
+//e.g. "books_handler;db 10000" for the "db" service created for the "books_handler"
+func (pr *ProfileReport) Folded() string {
+	builder := &strings.Builder{}
+	var writeNode func(node *ProfileNode, stack string)
+	writeNode = func(node *ProfileNode, stack string) {
+		if stack != "" {
+			stack += ";"
+		}
+		stack += node.ID
+		builder.WriteString(fmt.Sprintf("%s %d\n", stack, node.OwnDuration.Microseconds()))
+
+		for _, dependency := range node.Dependencies {
+			writeNode(dependency, stack)
+		}
+	}
+
+	for _, service := range pr.Services {
+		writeNode(service, "")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,176 @@
+package container
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+//formatProfileTree gives ids of profiled services as an indented tree
+func formatProfileTree(nodes []*ProfileNode, depth int) string {
+	tree := ""
+	for _, node := range nodes {
+		tree += strings.Repeat("  ", depth) + node.ID + "\n" + formatProfileTree(node.Dependencies, depth+1)
+	}
+
+	return tree
+}
+
+func TestProfiler(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func() *mocks.FakeDb {
+		time.Sleep(30 * time.Millisecond)
+		return &mocks.FakeDb{}
+	})
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "db")
+	cont.AddNewMethod("authors_storage", mocks.NewAuthorsStorage, "db")
+	cont.AddNewMethod("statistics_gateway", mocks.NewStatisticsGateway)
+	cont.AddDependencyObserver("statistics_provider", "statistics_gateway", func(sg *mocks.StatisticsGateway, sp mocks.StatisticsProvider) {
+		sg.AddStatisticsProvider(sp)
+	})
+	cont.RegisterDependencyEvent("statistics_provider", "book_storage")
+
+	profiler := NewProfiler(20 * time.Millisecond)
+	cont.AddResolutionHook(profiler)
+
+	cont.Get("statistics_gateway", true)
+	cont.Get("authors_storage", true)
+	cont.Get("book_storage", true)
+
+	report := profiler.Profile()
+	expectedTree := "statistics_gateway\n  book_storage\n    db\nauthors_storage\n"
+	tree := formatProfileTree(report.Services, 0)
+	if tree != expectedTree {
+		t.Errorf("The tree\n%s\nof created services is expected, but\n%s\nis given", expectedTree, tree)
+	}
+
+	gateway := report.Services[0]
+	storage := gateway.Dependencies[0]
+	db := storage.Dependencies[0]
+	if !db.IsSlow || db.OwnDuration < 30*time.Millisecond || db.OwnDuration != db.TotalDuration {
+		t.Errorf("The db should be flagged as a slow service, but %+v is given", db)
+	}
+	if storage.IsSlow || gateway.IsSlow ||
+		storage.TotalDuration < db.TotalDuration || gateway.TotalDuration < storage.TotalDuration {
+		t.Errorf("Only the own construction time should be flagged, but %+v and %+v are given", storage, gateway)
+	}
+	if storage.OwnDuration != storage.TotalDuration-db.TotalDuration {
+		t.Errorf("The own construction time should not include dependencies, but %+v is given", storage)
+	}
+
+	profiler.Reset()
+	cont.Get("book_storage", true)
+	cont.Get("authors_storage", false)
+	tree = formatProfileTree(profiler.Profile().Services, 0)
+	if tree != "authors_storage\n  db\n" {
+		t.Errorf("Only services created after the reset are expected, but\n%s\nis given", tree)
+	}
+}
+
+func TestProfilerOfConcurrentResolutions(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func() *mocks.FakeDb { return &mocks.FakeDb{} })
+	//the storage is still under construction when dbs of other resolutions are created
+	cont.AddNewMethod("book_storage", func(db *mocks.FakeDb) mocks.BookStorage {
+		time.Sleep(10 * time.Millisecond)
+		return mocks.NewBookStorage(db)
+	}, "db")
+
+	profiler := NewProfiler(0)
+	cont.AddResolutionHook(profiler)
+
+	resolutionsCount := 10
+	wg := sync.WaitGroup{}
+	wg.Add(resolutionsCount)
+	for i := 0; i < resolutionsCount; i++ {
+		go func() {
+			defer wg.Done()
+			cont.Get("book_storage", false)
+		}()
+	}
+	wg.Wait()
+
+	report := profiler.Profile()
+	if len(report.Services) != resolutionsCount {
+		t.Fatalf("A tree per resolution is expected, but\n%s\nis given", formatProfileTree(report.Services, 0))
+	}
+	for _, service := range report.Services {
+		if len(service.Dependencies) != 1 {
+			t.Fatalf(
+				"Every storage should have its own db, but\n%s\nis given",
+				formatProfileTree(report.Services, 0),
+			)
+		}
+	}
+}
+
+func TestProfilerCheck(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("cache", mocks.NewInMemoryCache)
+	cont.AddNewMethod("cache_manager", mocks.NewCacheManager, "cache")
+	cont.AddNewMethod("failing_manager", mocks.NewCacheManager, "unknown_cache")
+
+	profiler := NewProfiler(0)
+	cont.AddResolutionHook(profiler)
+	cont.Check()
+
+	report := profiler.Profile()
+	var failingManager *ProfileNode
+	for _, service := range report.Services {
+		if service.ID == "failing_manager" {
+			failingManager = service
+		}
+		if service.IsSlow {
+			t.Errorf("A zero threshold should disable flagging, but %+v is given", service)
+		}
+	}
+
+	if len(report.Services) != 3 || failingManager == nil {
+		t.Fatalf("All services should be created by the Check call, but\n%s\nis given", formatProfileTree(report.Services, 0))
+	}
+	if failingManager.Error != "Unknown dependency 'unknown_cache' [check 'failing_manager' service]" {
+		t.Errorf("The failed construction should be reported, but %+v is given", failingManager)
+	}
+}
+
+func TestProfileReportFormats(t *testing.T) {
+	report := &ProfileReport{
+		Threshold: 5 * time.Millisecond,
+		Services: []*ProfileNode{
+			{
+				ID:            "books_handler",
+				TotalDuration: 12 * time.Millisecond,
+				OwnDuration:   2 * time.Millisecond,
+				Dependencies: []*ProfileNode{
+					{ID: "db", TotalDuration: 10 * time.Millisecond, OwnDuration: 10 * time.Millisecond, IsSlow: true},
+				},
+			},
+			{ID: "cache", TotalDuration: time.Millisecond, OwnDuration: time.Millisecond, Error: "Cache is not available"},
+		},
+	}
+
+	expectedText := "books_handler total=12ms own=2ms\n" +
+		"  db total=10ms own=10ms SLOW\n" +
+		"cache total=1ms own=1ms error=\"Cache is not available\"\n"
+	if report.Text() != expectedText {
+		t.Errorf("The text\n%s\nis expected, but\n%s\nis given", expectedText, report.Text())
+	}
+
+	expectedFolded := "books_handler 2000\nbooks_handler;db 10000\ncache 1000\n"
+	if report.Folded() != expectedFolded {
+		t.Errorf("The folded stacks\n%s\nare expected, but\n%s\nare given", expectedFolded, report.Folded())
+	}
+
+	jsonReport, err := report.JSON()
+	assertNoError(err, t)
+	expectedJSON := `{"threshold":5000000,"services":[` +
+		`{"id":"books_handler","own_duration":2000000,"total_duration":12000000,"is_slow":false,"dependencies":[` +
+		`{"id":"db","own_duration":10000000,"total_duration":10000000,"is_slow":true}]},` +
+		`{"id":"cache","own_duration":1000000,"total_duration":1000000,"is_slow":false,"error":"Cache is not available"}]}`
+	if string(jsonReport) != expectedJSON {
+		t.Errorf("The JSON\n%s\nis expected, but\n%s\nis given", expectedJSON, jsonReport)
+	}
+}
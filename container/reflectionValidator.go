@@ -10,11 +10,15 @@ func isFunction(reflectedConstructorFunc reflect.Value) bool {
 	return reflectedConstructorFunc.Kind() == reflect.Func
 }
 
-func hasArgumentsCount(reflectedConstructorFunc reflect.Value, expectedArgNumbersCount int) (bool, int) {
+func hasArgumentsCount(
+	reflectedConstructorFunc reflect.Value,
+	expectedArgNumbersCount,
+	skippedArgNumbersCount int,
+) (bool, int) {
 	if reflectedConstructorFunc.Type().IsVariadic() {
 		return true, expectedArgNumbersCount
 	}
-	argsInputCount := reflectedConstructorFunc.Type().NumIn()
+	argsInputCount := reflectedConstructorFunc.Type().NumIn() - skippedArgNumbersCount
 	return argsInputCount == expectedArgNumbersCount, argsInputCount
 }
 
@@ -22,6 +26,30 @@ func assertFunctionDeclaration(
 	reflectedConstructorFunc reflect.Value,
 	expectedArgumentsCount int,
 	serviceId string,
+) error {
+	return assertFunctionArguments(reflectedConstructorFunc, expectedArgumentsCount, 0, serviceId)
+}
+
+//assertNewMethodDeclaration does the same as assertFunctionDeclaration but doesn't count the leading context argument
+//of a New method unless it's declared as a dependency
+func assertNewMethodDeclaration(
+	reflectedNewMethod reflect.Value,
+	expectedArgumentsCount int,
+	serviceId string,
+) error {
+	skippedArgumentsCount := 0
+	if isFunction(reflectedNewMethod) {
+		skippedArgumentsCount = skippedContextArgumentsCount(reflectedNewMethod.Type(), expectedArgumentsCount)
+	}
+
+	return assertFunctionArguments(reflectedNewMethod, expectedArgumentsCount, skippedArgumentsCount, serviceId)
+}
+
+func assertFunctionArguments(
+	reflectedConstructorFunc reflect.Value,
+	expectedArgumentsCount,
+	skippedArgumentsCount int,
+	serviceId string,
 ) error {
 	if !isFunction(reflectedConstructorFunc) {
 		errName := fmt.Sprintf(
@@ -32,7 +60,7 @@ func assertFunctionDeclaration(
 		return errors.New(errName)
 	}
 
-	hasArgCount, argsCount := hasArgumentsCount(reflectedConstructorFunc, expectedArgumentsCount)
+	hasArgCount, argsCount := hasArgumentsCount(reflectedConstructorFunc, expectedArgumentsCount, skippedArgumentsCount)
 
 	if !hasArgCount {
 		errName := fmt.Sprintf(
@@ -83,28 +111,30 @@ func validateConstructorReturnValues(reflectedConstructorFunc reflect.Value, ser
 func assertCompatible(expectedDependency, providedDependency reflect.Type, dependencyName, serviceId string) error {
 	isCompat := false
 	if providedDependency == nil {
-		k := expectedDependency.Kind()
-		isCompat = k == reflect.Chan ||
-			k == reflect.Func ||
-			k == reflect.Interface ||
-			k == reflect.Map ||
-			k == reflect.Ptr ||
-			k == reflect.Slice
-
+		isCompat = isNillable(expectedDependency)
 	} else {
 		isCompat = providedDependency.AssignableTo(expectedDependency)
 	}
 
 	if !isCompat {
-		errName := fmt.Sprintf(
-			"Cannot use the provided dependency '%s' of type '%s' as '%s' in the Constr function call [check '%s' service]",
-			dependencyName,
-			providedDependency,
-			expectedDependency,
-			serviceId,
-		)
-		return errors.New(errName)
+		return &TypeMismatchError{
+			ServiceID: serviceId,
+			Arg:       dependencyName,
+			Expected:  expectedDependency,
+			Provided:  providedDependency,
+		}
 	}
 
 	return nil
 }
+
+//isNillable checks if a nil value can be used as a value of the provided type
+func isNillable(reflectedType reflect.Type) bool {
+	k := reflectedType.Kind()
+	return k == reflect.Chan ||
+		k == reflect.Func ||
+		k == reflect.Interface ||
+		k == reflect.Map ||
+		k == reflect.Ptr ||
+		k == reflect.Slice
+}
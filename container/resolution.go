@@ -0,0 +1,190 @@
+package container
+
+import (
+	"context"
+	"fmt"
+)
+
+//resolution is a view of a single top level service request on a container, so every call of GetSecure on the
+//RuntimeContainer gets its own cycle detector and concurrent requests don't interfere with each other.
+//Constructors receive the resolution as their Container, so nested dependency requests are tracked within the same call
+type resolution struct {
+	*RuntimeContainer
+	*resolutionState
+	//path is the chain of services from the top level request to the service created with this view
+	path []string
+}
+
+//resolutionState is shared by all views of the same resolution when it goes through the chain of scopes
+type resolutionState struct {
+	//ctx is passed to context aware constructors, the resolution is aborted when it's done
+	ctx           context.Context
+	cycleDetector *CycleDetector
+	//origin is the container of the top level request, scoped services live there
+	origin *RuntimeContainer
+	//waitingFor is the in-flight construction this resolution is blocked on, it's guarded by the waits mutex
+	waitingFor *inFlightCall
+	//parent is the resolution which waits for this branch, it's set once when the branch is forked, branches are
+	//the running parallel branches of this resolution guarded by the waits mutex
+	parent   *resolutionState
+	branches map[*resolutionState]bool
+}
+
+//topLevelRequest gives the state of the top level request of the resolution, branches of the resolution share it
+func (rs *resolutionState) topLevelRequest() *resolutionState {
+	for rs.parent != nil {
+		rs = rs.parent
+	}
+
+	return rs
+}
+
+func newResolution(rc *RuntimeContainer) *resolution {
+	return newContextResolution(context.Background(), rc)
+}
+
+func newContextResolution(ctx context.Context, rc *RuntimeContainer) *resolution {
+	return &resolution{
+		RuntimeContainer: rc,
+		resolutionState:  &resolutionState{ctx: ctx, cycleDetector: NewCycleDetector(), origin: rc},
+	}
+}
+
+//next gives a view of the current resolution on a container of the scopes chain for creation of the service
+//identified by id, the path of the view is extended with id
+func (r *resolution) next(rc *RuntimeContainer, id string) *resolution {
+	path := make([]string, len(r.path), len(r.path)+1)
+	copy(path, r.path)
+
+	return &resolution{
+		RuntimeContainer: rc,
+		resolutionState:  r.resolutionState,
+		path:             append(path, id),
+	}
+}
+
+//Scan copies a Service identified by id into a typed destination (its a pointer reference) and panics on failure
+func (r *resolution) Scan(id string, dest interface{}) {
+	err := r.ScanSecure(id, true, dest)
+	if err != nil {
+		panic(err)
+	}
+}
+
+//ScanNonCached creates a Service every time this method is called and panics on failure
+func (r *resolution) ScanNonCached(id string, dest interface{}) {
+	err := r.ScanSecure(id, false, dest)
+	if err != nil {
+		panic(err)
+	}
+}
+
+//ScanSecure copies a service identified by id into a typed destination and returns error on failure
+func (r *resolution) ScanSecure(id string, isCached bool, dest interface{}) error {
+	baseValue, err := r.GetSecure(id, isCached)
+	if err != nil {
+		return err
+	}
+
+	return copySourceVariableToDestinationVariable(baseValue, dest, id)
+}
+
+//Get fetches a Service in a return argument and panics if an error happens
+func (r *resolution) Get(id string, isCached bool) interface{} {
+	dependency, err := r.GetSecure(id, isCached)
+	if err != nil {
+		panic(err)
+	}
+
+	return dependency
+}
+
+//GetSecure fetches a Service within the current resolution and returns an error rather than panics
+func (r *resolution) GetSecure(id string, isCached bool) (interface{}, error) {
+	if r.ctx.Err() != nil {
+		return nil, newAbortedResolutionError(r.ctx, id)
+	}
+
+	if dependency, isOptional := parseOptionalDependency(id); isOptional {
+		return r.getOptional(dependency, isCached)
+	}
+
+	if tag, isTagReference := parseTagReference(id); isTagReference {
+		return r.getTagged(tag, isCached)
+	}
+
+	if target, isAlias := r.findAlias(id); isAlias {
+		return r.getAliased(id, target, isCached)
+	}
+
+	lifetime := r.getLifetime(id)
+	target := r.RuntimeContainer
+	if lifetime != Scoped && lifetime != Transient {
+		//shared services live in the container where they are declared rather than in the current scope
+		if owner := target.findOwner(id); owner != nil {
+			target = owner
+		}
+	}
+
+	return r.getDeclared(target, id, lifetime, isCached)
+}
+
+//getDeclared creates the service identified by id in the target container or takes it from its cache,
+//the id should be neither an alias nor a reference to optional or tagged services
+func (r *resolution) getDeclared(
+	target *RuntimeContainer,
+	id string,
+	lifetime Lifetime,
+	isCached bool,
+) (interface{}, error) {
+	if lifetime == Scoped && len(r.path) > 0 && r.RuntimeContainer != r.origin {
+		//a service cached outside of the requesting scope would keep the scoped instance after the scope is gone
+		return nil, fmt.Errorf(
+			"The scoped service '%s' cannot be a dependency of a service shared between scopes [check '%s' service]",
+			id,
+			r.path[len(r.path)-1],
+		)
+	}
+
+	r.cycleDetector.VisitBeforeRecursion(id)
+
+	if r.cycleDetector.IsEnabled() && r.cycleDetector.HasCycle() {
+		return nil, &CycleError{Path: r.cycleDetector.GetCycle()}
+	}
+
+	//the lifetime decides only how the service itself is cached, its dependencies get the flag of the caller
+	return r.resolveWithHooks(target, id, lifetime.isCached(isCached), isCached)
+}
+
+//isBlockedBy tells if the current resolution is (transitively) the owner of the provided in-flight construction,
+//so waiting for it would never end, must be called under the waits mutex
+func (rs *resolutionState) isBlockedBy(call *inFlightCall) bool {
+	return rs.isAwaitedBy(call.owner, map[*resolutionState]bool{})
+}
+
+//isAwaitedBy tells if the resolution or one of its parents waits for the current one, a resolution with running
+//branches waits for all of them
+func (rs *resolutionState) isAwaitedBy(owner *resolutionState, checkedOwners map[*resolutionState]bool) bool {
+	if owner == nil || checkedOwners[owner] {
+		return false
+	}
+	checkedOwners[owner] = true
+
+	for parent := rs; parent != nil; parent = parent.parent {
+		if owner == parent {
+			return true
+		}
+	}
+
+	if owner.waitingFor != nil {
+		return rs.isAwaitedBy(owner.waitingFor.owner, checkedOwners)
+	}
+
+	for branch := range owner.branches {
+		if rs.isAwaitedBy(branch, checkedOwners) {
+			return true
+		}
+	}
+
+	return false
+}
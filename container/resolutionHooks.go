@@ -0,0 +1,83 @@
+package container
+
+import "time"
+
+//ResolutionHook observes how services are created and how observers get their dependencies, e.g. for logging, metrics
+//or tracing. Hooks are called synchronously in the goroutine of the resolution, so they should be fast and safe for
+//concurrent use
+type ResolutionHook interface {
+	//OnResolveStart is called before the service identified by id is taken from the cache or created
+	OnResolveStart(id string)
+	//OnResolveEnd is called after the resolution of the service, the duration includes the resolution of its
+	//dependencies, fromCache tells that the service was not created by this resolution
+	OnResolveEnd(id string, duration time.Duration, err error, fromCache bool)
+	//OnEventDispatched is called after the observer got the dependency registered for the event
+	OnEventDispatched(event, observer, dependency string)
+}
+
+//tracingResolutionHook is a hook which also gets the chain of services from the top level request to the resolved one,
+//the top level request identifies the resolution as concurrent resolutions can go through the same chain
+type tracingResolutionHook interface {
+	onResolvePathEnd(request *resolutionState, path []string, duration time.Duration, err error, fromCache bool)
+}
+
+//AddResolutionHook registers a hook which is notified about resolutions in the container and all its scopes,
+//hooks added to a scope are notified only about resolutions in it
+func (rc *RuntimeContainer) AddResolutionHook(hook ResolutionHook) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	rc.resolutionHooks = append(rc.resolutionHooks, hook)
+}
+
+//getResolutionHooks gives hooks of the scopes chain starting from the current scope
+func (rc *RuntimeContainer) getResolutionHooks() []ResolutionHook {
+	var hooks []ResolutionHook
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		hooks = append(hooks, cont.resolutionHooks...)
+		cont.mutex.RUnlock()
+	}
+
+	return hooks
+}
+
+//resolveWithHooks takes the service identified by id from the cache of the target container or creates it
+//and notifies resolution hooks about it
+func (r *resolution) resolveWithHooks(
+	target *RuntimeContainer,
+	id string,
+	isCached,
+	isDependencyCached bool,
+) (interface{}, error) {
+	serviceResolution := r.next(target, id)
+	hooks := r.getResolutionHooks()
+	if len(hooks) == 0 {
+		service, _, err := target.resolve(serviceResolution, id, isCached, isDependencyCached)
+		return service, err
+	}
+
+	for _, hook := range hooks {
+		hook.OnResolveStart(id)
+	}
+
+	startTime := time.Now()
+	service, fromCache, err := target.resolve(serviceResolution, id, isCached, isDependencyCached)
+	duration := time.Since(startTime)
+
+	for _, hook := range hooks {
+		hook.OnResolveEnd(id, duration, err, fromCache)
+		if tracingHook, isTracing := hook.(tracingResolutionHook); isTracing {
+			tracingHook.onResolvePathEnd(r.topLevelRequest(), serviceResolution.path, duration, err, fromCache)
+		}
+	}
+
+	return service, err
+}
+
+//notifyEventDispatched tells resolution hooks that the observer got the dependency registered for the event
+func (rc *RuntimeContainer) notifyEventDispatched(event, observer, dependency string) {
+	for _, hook := range rc.getResolutionHooks() {
+		hook.OnEventDispatched(event, observer, dependency)
+	}
+}
@@ -0,0 +1,171 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+//recordingHook remembers all notifications in the order of calls
+type recordingHook struct {
+	calls []string
+	mutex sync.Mutex
+}
+
+func (rh *recordingHook) record(call string) {
+	rh.mutex.Lock()
+	defer rh.mutex.Unlock()
+
+	rh.calls = append(rh.calls, call)
+}
+
+func (rh *recordingHook) OnResolveStart(id string) {
+	rh.record("start " + id)
+}
+
+func (rh *recordingHook) OnResolveEnd(id string, duration time.Duration, err error, fromCache bool) {
+	rh.record(fmt.Sprintf("end %s cached=%t error=%v", id, fromCache, err))
+}
+
+func (rh *recordingHook) OnEventDispatched(event, observer, dependency string) {
+	rh.record(fmt.Sprintf("event %s %s %s", event, observer, dependency))
+}
+
+func (rh *recordingHook) getCalls() string {
+	rh.mutex.Lock()
+	defer rh.mutex.Unlock()
+
+	return strings.Join(rh.calls, "\n")
+}
+
+func assertCalls(hook *recordingHook, expectedCalls []string, t *testing.T) {
+	t.Helper()
+
+	if hook.getCalls() != strings.Join(expectedCalls, "\n") {
+		t.Errorf("Calls\n%s\nare expected, but\n%s\nare given", strings.Join(expectedCalls, "\n"), hook.getCalls())
+	}
+}
+
+func TestResolutionHooks(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("cache", mocks.NewInMemoryCache)
+	cont.AddNewMethod("cache_manager", mocks.NewCacheManager, "cache")
+	cont.AddConstructor("failing_cache", func(c Container) (interface{}, error) {
+		return nil, errors.New("Cache is not available")
+	})
+
+	hook := &recordingHook{}
+	cont.AddResolutionHook(hook)
+
+	cont.Get("cache_manager", true)
+	cont.Get("cache_manager", true)
+	cont.Get("cache", false)
+	cont.GetSecure("failing_cache", true)
+
+	assertCalls(hook, []string{
+		"start cache_manager",
+		"start cache",
+		"end cache cached=false error=<nil>",
+		"end cache_manager cached=false error=<nil>",
+		"start cache_manager",
+		"end cache_manager cached=true error=<nil>",
+		"start cache",
+		"end cache cached=false error=<nil>",
+		"start failing_cache",
+		"end failing_cache cached=false error=Cache is not available [check 'failing_cache' service]",
+	}, t)
+}
+
+func TestResolutionHooksInScope(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("cache", mocks.NewInMemoryCache)
+	containerHook := &recordingHook{}
+	cont.AddResolutionHook(containerHook)
+
+	scope := cont.NewScope()
+	scope.AddNewMethod("cache_manager", mocks.NewCacheManager, "cache")
+	scopeHook := &recordingHook{}
+	scope.AddResolutionHook(scopeHook)
+
+	scope.Get("cache_manager", true)
+	cont.Get("cache", true)
+
+	assertCalls(containerHook, []string{
+		"start cache_manager",
+		"start cache",
+		"end cache cached=false error=<nil>",
+		"end cache_manager cached=false error=<nil>",
+		"start cache",
+		"end cache cached=true error=<nil>",
+	}, t)
+	assertCalls(scopeHook, []string{
+		"start cache_manager",
+		"start cache",
+		"end cache cached=false error=<nil>",
+		"end cache_manager cached=false error=<nil>",
+	}, t)
+}
+
+func TestEventDispatchedHooks(t *testing.T) {
+	cont := CreateContainer()
+	hook := &recordingHook{}
+	cont.AddResolutionHook(hook)
+
+	cont.Get("statistics_gateway", true)
+
+	calls := hook.getCalls()
+	for _, expectedCall := range []string{
+		"event statistics_provider statistics_gateway book_storage",
+		"event statistics_provider statistics_gateway authors_storage",
+	} {
+		if !strings.Contains(calls, expectedCall) {
+			t.Errorf("The call '%s' is expected in\n%s", expectedCall, calls)
+		}
+	}
+}
+
+func TestMetricsCollector(t *testing.T) {
+	cont := CreateContainer()
+	cont.AddConstructor("failing_cache", func(c Container) (interface{}, error) {
+		return nil, errors.New("Cache is not available")
+	})
+	collector := NewMetricsCollector()
+	cont.AddResolutionHook(collector)
+
+	cont.Get("statistics_gateway", true)
+	cont.Get("statistics_gateway", true)
+	cont.Get("book_storage", false)
+	cont.GetSecure("failing_cache", true)
+
+	metrics := collector.Metrics()
+	gatewayMetrics := metrics["statistics_gateway"]
+	if gatewayMetrics.Resolutions != 2 || gatewayMetrics.Creations != 1 || gatewayMetrics.Errors != 0 {
+		t.Errorf("A created and a cached resolution are expected, but %+v is given", gatewayMetrics)
+	}
+	if gatewayMetrics.TotalDuration < gatewayMetrics.MaxDuration || gatewayMetrics.MaxDuration <= 0 {
+		t.Errorf("Durations of resolutions should be aggregated, but %+v is given", gatewayMetrics)
+	}
+
+	storageMetrics := metrics["book_storage"]
+	if storageMetrics.Resolutions != 2 || storageMetrics.Creations != 2 {
+		t.Errorf("Book storage should be created for the event and the non cached request, but %+v is given", storageMetrics)
+	}
+
+	if metrics["failing_cache"].Errors != 1 || metrics["failing_cache"].Creations != 0 {
+		t.Errorf("A failed resolution is expected, but %+v is given", metrics["failing_cache"])
+	}
+
+	if collector.DispatchedEvents()["statistics_provider"] != 2 {
+		t.Errorf("Two dispatched dependencies are expected, but %v is given", collector.DispatchedEvents())
+	}
+
+	collector.Reset()
+	if len(collector.Metrics()) != 0 || len(collector.DispatchedEvents()) != 0 {
+		t.Error("Metrics should be removed")
+	}
+}
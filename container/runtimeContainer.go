@@ -2,18 +2,38 @@ package container
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
+	"sync"
 )
 
-//RuntimeContainer creates Services at runtime with registered callbacks
+//RuntimeContainer creates Services at runtime with registered callbacks, it's safe for concurrent use
 type RuntimeContainer struct {
 	constructors        map[string]Constructor
 	newFuncConstructors map[string]NewFuncConstructor
+	serviceTypes        map[string]reflect.Type
+	newMethods          map[string]*newMethodDeclaration
+	structFields        map[string][]injectedField
+	parameters          map[string]interface{}
+	decorators          map[string][]*serviceDecorator
+	tags                map[string][]TaggedService
+	aliases             map[string]string
+	bindings            map[reflect.Type]string
+	sources             map[string]uintptr
+	lifetimes           map[string]Lifetime
 	cache               dependencyCache
+	creationNumbers     map[string]uint64
+	inFlightCalls       map[string]*inFlightCall
 	eventsContainer     *EventsContainer
 	garbageCollectors   *GarbageCollectorFuncs
-	cycleDetector       *CycleDetector
-	rootDependency      string
+	lifecycleHooks      *lifecycleHooks
+	resolutionHooks     []ResolutionHook
+	parent              *RuntimeContainer
+	workerPool          *workerPool
+	isFrozen            bool
+	mutex               sync.RWMutex
+	//waitsMutex guards relations between waiting resolutions, it's shared by a container and all its scopes
+	waitsMutex *sync.Mutex
 }
 
 //NewRuntimeContainer creates container
@@ -21,27 +41,71 @@ func NewRuntimeContainer() *RuntimeContainer {
 	return &RuntimeContainer{
 		constructors:        make(map[string]Constructor),
 		cache:               newDependencyCache(),
+		creationNumbers:     make(map[string]uint64),
 		eventsContainer:     NewEventsContainer(),
 		garbageCollectors:   NewGarbageCollectorFuncs(),
+		lifecycleHooks:      newLifecycleHooks(),
 		newFuncConstructors: make(map[string]NewFuncConstructor),
-		cycleDetector:       NewCycleDetector(),
+		inFlightCalls:       make(map[string]*inFlightCall),
+		serviceTypes:        make(map[string]reflect.Type),
+		newMethods:          make(map[string]*newMethodDeclaration),
+		structFields:        make(map[string][]injectedField),
+		parameters:          make(map[string]interface{}),
+		decorators:          make(map[string][]*serviceDecorator),
+		tags:                make(map[string][]TaggedService),
+		aliases:             make(map[string]string),
+		bindings:            make(map[reflect.Type]string),
+		sources:             make(map[string]uintptr),
+		lifetimes:           make(map[string]Lifetime),
+		waitsMutex:          &sync.Mutex{},
 	}
 }
 
 //AddConstructor registers a Callback to create a Service identified by id, panics if id was already declared
 func (rc *RuntimeContainer) AddConstructor(id string, constructor Constructor) error {
-	err := rc.assertNoDuplicates(id)
+	err := assertValidID(id)
 	if err != nil {
 		return err
 	}
-	rc.SetConstructor(id, constructor)
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	err = rc.assertNotFrozen(id)
+	if err != nil {
+		return err
+	}
+
+	err = rc.assertNoDuplicates(id)
+	if err != nil {
+		return err
+	}
+	rc.constructors[id] = constructor
+	rc.sources[id] = reflect.ValueOf(constructor).Pointer()
 
 	return nil
 }
 
-//SetConstructor adds a new service if it's not existing or overrides an existing one
+//SetConstructor adds a new service if it's not existing or overrides an existing one, panics if the container is frozen
 func (rc *RuntimeContainer) SetConstructor(id string, constructor Constructor) {
+	err := assertValidID(id)
+	if err != nil {
+		panic(err)
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	err = rc.assertNotFrozen(id)
+	if err != nil {
+		panic(err)
+	}
+
 	rc.constructors[id] = constructor
+	rc.sources[id] = reflect.ValueOf(constructor).Pointer()
+	//constructors have priority over new methods, so the declared type of the service is unknown from now
+	delete(rc.serviceTypes, id)
+	delete(rc.parameters, id)
 }
 
 //AddNewMethod converts a New Service method to a valid Callback Constr, panics if id already exists
@@ -50,12 +114,12 @@ func (rc *RuntimeContainer) AddNewMethod(
 	typedConstructor interface{},
 	constructorArgumentNames ...string,
 ) error {
-	err := rc.assertNoDuplicates(id)
+	constrFunc, err := convertNewMethodToNewFuncConstructor(typedConstructor, constructorArgumentNames, id)
 	if err != nil {
 		return err
 	}
 
-	return rc.SetNewMethod(id, typedConstructor, constructorArgumentNames...)
+	return rc.setNewFuncConstructor(id, constrFunc, newNewMethodDeclaration(typedConstructor, constructorArgumentNames), true)
 }
 
 //SetNewMethod overrides an existing service declaration or adds a new one if it doesn't exist
@@ -64,22 +128,70 @@ func (rc *RuntimeContainer) SetNewMethod(
 	typedConstructor interface{},
 	constructorArgumentNames ...string,
 ) error {
-	constrFunc, err := convertNewMethodToNewFuncConstructor(
-		rc,
-		typedConstructor,
-		constructorArgumentNames,
-		id,
-	)
+	constrFunc, err := convertNewMethodToNewFuncConstructor(typedConstructor, constructorArgumentNames, id)
+	if err != nil {
+		return err
+	}
+
+	return rc.setNewFuncConstructor(id, constrFunc, newNewMethodDeclaration(typedConstructor, constructorArgumentNames), false)
+}
+
+//setSource records the entry point of a function which declares the service identified by id
+func (rc *RuntimeContainer) setSource(id string, source uintptr) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	rc.sources[id] = source
+}
+
+//setNewFuncConstructor registers a converted New method together with the declared type of its Service
+//and the source of the New method
+func (rc *RuntimeContainer) setNewFuncConstructor(
+	id string,
+	constrFunc NewFuncConstructor,
+	declaration *newMethodDeclaration,
+	isUnique bool,
+) error {
+	err := assertValidID(id)
+	if err != nil {
+		return err
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	err = rc.assertNotFrozen(id)
 	if err != nil {
 		return err
 	}
 
+	if isUnique {
+		err = rc.assertNoDuplicates(id)
+		if err != nil {
+			return err
+		}
+	}
+
 	rc.newFuncConstructors[id] = constrFunc
+	rc.newMethods[id] = declaration
+	delete(rc.structFields, id)
+	rc.serviceTypes[id] = getNewMethodServiceType(declaration.newMethod)
+	if _, isConstructor := rc.constructors[id]; !isConstructor {
+		rc.sources[id] = declaration.newMethod.Pointer()
+	}
+
 	return nil
 }
 
 //AddDependencyObserver registers Service that will receive Config it is interested in
 func (rc *RuntimeContainer) AddDependencyObserver(eventName, observerID string, observer interface{}) error {
+	rc.mutex.RLock()
+	err := rc.assertNotFrozen(observerID)
+	rc.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
 	return rc.eventsContainer.addDependencyObserver(eventName, observerID, observer)
 }
 
@@ -126,77 +238,154 @@ func (rc *RuntimeContainer) Get(id string, isCached bool) interface{} {
 
 //GetSecure fetches a Service in a return argument and returns an error rather than panics
 func (rc *RuntimeContainer) GetSecure(id string, isCached bool) (interface{}, error) {
-	if rc.rootDependency == "" {
-		rc.rootDependency = id
+	return newResolution(rc).GetSecure(id, isCached)
+}
+
+//resolve returns a cached service or creates a new one, fromCache tells that the service was not created by this call,
+//isDependencyCached is passed to dependencies of the created service
+func (rc *RuntimeContainer) resolve(
+	r *resolution,
+	id string,
+	isCached,
+	isDependencyCached bool,
+) (service interface{}, fromCache bool, err error) {
+	if !isCached {
+		service, err = rc.build(r, id, isDependencyCached)
+		return service, false, err
+	}
+
+	return rc.resolveCached(r, id, isDependencyCached)
+}
+
+//resolveCached returns a cached service or creates it, concurrent requests of the same service
+//will wait for the first one, so the service is created exactly once
+func (rc *RuntimeContainer) resolveCached(r *resolution, id string, isDependencyCached bool) (interface{}, bool, error) {
+	rc.mutex.Lock()
+	dependency, ok := rc.cache.Get(id)
+	if ok {
+		rc.mutex.Unlock()
+		r.cycleDetector.VisitAfterRecursion(id)
+		return dependency, true, nil
 	}
 
-	defer rc.resetCycleDetectorIfNeeded(id)
+	call, isInFlight := rc.inFlightCalls[id]
+	if !isInFlight {
+		call = newInFlightCall(r.resolutionState, id)
+		rc.inFlightCalls[id] = call
+		rc.mutex.Unlock()
+
+		defer rc.finishInFlightCall(id, call)
 
-	rc.cycleDetector.VisitBeforeRecursion(id)
+		call.service, call.err = rc.build(r, id, isDependencyCached)
 
-	if rc.cycleDetector.IsEnabled() && rc.cycleDetector.HasCycle() {
-		return nil, fmt.Errorf("Detected dependencies' cycle: %s", strings.Join(rc.cycleDetector.GetCycle(), "->"))
+		return call.service, false, call.err
 	}
+	rc.mutex.Unlock()
 
-	dependency, ok := rc.cache.Get(id)
-	if ok && isCached {
-		rc.cycleDetector.VisitAfterRecursion(id)
-		return dependency, nil
+	if !rc.startWaiting(r, call) {
+		//the running construction waits for the current resolution, which means a dependency cycle,
+		//so we build the service here and let the cycle detector report it
+		service, err := rc.build(r, id, isDependencyCached)
+		return service, false, err
 	}
 
-	constructorFunc, ok := rc.constructors[id]
+	select {
+	case <-call.done:
+		rc.stopWaiting(r)
+	case <-r.ctx.Done():
+		rc.stopWaiting(r)
+		return nil, false, newAbortedResolutionError(r.ctx, id)
+	}
+
+	if call.err == nil {
+		r.cycleDetector.VisitAfterRecursion(id)
+	}
+
+	return call.service, true, call.err
+}
+
+//startWaiting registers that the resolution waits for the in-flight call unless it would wait for itself
+func (rc *RuntimeContainer) startWaiting(r *resolution, call *inFlightCall) bool {
+	rc.waitsMutex.Lock()
+	defer rc.waitsMutex.Unlock()
+
+	if r.isBlockedBy(call) {
+		return false
+	}
+	r.waitingFor = call
+
+	return true
+}
+
+func (rc *RuntimeContainer) stopWaiting(r *resolution) {
+	rc.waitsMutex.Lock()
+	defer rc.waitsMutex.Unlock()
+
+	r.waitingFor = nil
+}
+
+func (rc *RuntimeContainer) finishInFlightCall(id string, call *inFlightCall) {
+	rc.mutex.Lock()
+	delete(rc.inFlightCalls, id)
+	rc.mutex.Unlock()
+
+	close(call.done)
+}
+
+//build creates a new instance of a Service with the registered constructor and puts it to the cache
+func (rc *RuntimeContainer) build(r *resolution, id string, isCached bool) (interface{}, error) {
+	owner := rc.findOwner(id)
+	if owner == nil {
+		return nil, &UnknownServiceError{ServiceID: id}
+	}
+
+	owner.mutex.RLock()
+	constructorFunc, isConstructor := owner.constructors[id]
+	newFuncConstructor := owner.newFuncConstructors[id]
+	source := owner.sources[id]
+	owner.mutex.RUnlock()
+
 	var service interface{}
 	var err error
-	if !ok {
-		newFuncConstructor, ok := rc.newFuncConstructors[id]
-		if !ok {
-			return dependency, fmt.Errorf("Unknown dependency '%s'", id)
-		}
-		service, err = newFuncConstructor(rc, isCached)
+	if isConstructor {
+		service, err = constructorFunc(r)
 	} else {
-		service, err = constructorFunc(rc)
+		service, err = newFuncConstructor(r, isCached)
 	}
 
 	if err != nil {
-		errorMsgSuffix := fmt.Sprintf(" [check '%s' service]", id)
-		if strings.Contains(err.Error(), errorMsgSuffix) {
-			errorMsgSuffix = ""
-		}
+		return service, &ConstructorError{ServiceID: id, Cause: err, Path: r.path, Source: getFuncSource(source)}
+	}
 
-		return service, fmt.Errorf("%v%s", err, errorMsgSuffix)
+	service, err = owner.decorate(r, id, service, isCached)
+	if err != nil {
+		return service, err
 	}
 
-	rc.cycleDetector.VisitAfterRecursion(id)
+	r.cycleDetector.VisitAfterRecursion(id)
 
-	err = rc.eventsContainer.collectDependencyEventsForService(rc, id, service)
+	err = rc.collectDependencyEvents(r, id, service)
 	if err != nil {
 		return nil, err
 	}
 
+	if rc.getLifetime(id) == Transient {
+		return service, nil
+	}
+
+	rc.mutex.Lock()
 	rc.cache.Set(id, service)
+	rc.creationNumbers[id] = nextCreationNumber()
+	rc.mutex.Unlock()
 
 	return service, nil
 }
 
-func (rc *RuntimeContainer) resetCycleDetectorIfNeeded(curDependency string) {
-	if rc.rootDependency == curDependency {
-		rc.rootDependency = ""
-		rc.cycleDetector.Reset()
-	}
-}
-
 //Check ensures that all runtime Config are created correctly
 func (rc *RuntimeContainer) Check() error {
 	errs := []error{}
 	var err error
-	for dependencyName := range rc.constructors {
-		_, err = rc.GetSecure(dependencyName, false)
-		if err != nil {
-			errs = append(errs, err)
-		}
-	}
-
-	for dependencyName := range rc.newFuncConstructors {
+	for _, dependencyName := range rc.getServiceIDs() {
 		_, err = rc.GetSecure(dependencyName, false)
 		if err != nil {
 			errs = append(errs, err)
@@ -211,15 +400,78 @@ func (rc *RuntimeContainer) Check() error {
 	return mergeErrors(errs)
 }
 
-//Exists ensures that all runtime Config are created correctly
+//Exists tells if a service or an alias identified by id is declared in the container or its parents
 func (rc *RuntimeContainer) Exists(id string) bool {
-	_, exists := rc.constructors[id]
-	return exists
+	if rc.findOwner(id) != nil {
+		return true
+	}
+
+	_, isAlias := rc.findAlias(id)
+
+	return isAlias
+}
+
+//getServiceIDs gives ids of all declared services in the scopes chain, constructors go first as they did in the
+//Check function before
+func (rc *RuntimeContainer) getServiceIDs() []string {
+	ids := []string{}
+	knownIDs := map[string]bool{}
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		for id := range cont.constructors {
+			if !knownIDs[id] {
+				ids = append(ids, id)
+				knownIDs[id] = true
+			}
+		}
+
+		for id := range cont.newFuncConstructors {
+			if !knownIDs[id] {
+				ids = append(ids, id)
+				knownIDs[id] = true
+			}
+		}
+		cont.mutex.RUnlock()
+	}
+
+	return ids
 }
 
 //Merge allows to merge containers
 func (rc *RuntimeContainer) Merge(c MergeableContainer) error {
-	for keyConstructor, constr := range c.getConstructors() {
+	constructors := c.getConstructors()
+	newFuncConstructors := c.getNewFuncConstructors()
+	serviceTypes := c.getServiceTypes()
+	newMethods := c.getNewMethods()
+	structFields := c.getStructFields()
+	parameters := c.getParameters()
+	decorators := c.getDecorators()
+	tags := c.getTags()
+	aliases := c.getAliases()
+	bindings := c.getBindings()
+	sources := c.getSources()
+	lifetimes := c.getLifetimes()
+	cache := c.getCache()
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	if rc.isFrozen {
+		return fmt.Errorf("Cannot merge containers because the container is frozen")
+	}
+
+	for alias, target := range aliases {
+		if rc.assertNoDuplicates(alias) != nil {
+			return fmt.Errorf("Cannot merge containers because of non unique Service id '%s'", alias)
+		}
+		rc.aliases[alias] = target
+	}
+
+	for interfaceType, interfaceID := range bindings {
+		rc.bindings[interfaceType] = interfaceID
+	}
+
+	for keyConstructor, constr := range constructors {
 		if _, ok := rc.constructors[keyConstructor]; ok {
 			return fmt.Errorf(
 				"Cannot merge containers because of non unique Service id '%s'",
@@ -230,7 +482,7 @@ func (rc *RuntimeContainer) Merge(c MergeableContainer) error {
 		rc.constructors[keyConstructor] = constr
 	}
 
-	for keyConstructor, constr := range c.getNewFuncConstructors() {
+	for keyConstructor, constr := range newFuncConstructors {
 		if _, ok := rc.newFuncConstructors[keyConstructor]; ok {
 			return fmt.Errorf(
 				"Cannot merge containers because of non unique Service id '%s'",
@@ -240,8 +492,40 @@ func (rc *RuntimeContainer) Merge(c MergeableContainer) error {
 		rc.newFuncConstructors[keyConstructor] = constr
 	}
 
-	for keyCache, cache := range c.getCache() {
-		rc.cache[keyCache] = cache
+	for keyServiceType, serviceType := range serviceTypes {
+		rc.serviceTypes[keyServiceType] = serviceType
+	}
+
+	for keyNewMethod, newMethod := range newMethods {
+		rc.newMethods[keyNewMethod] = newMethod
+	}
+
+	for keyStruct, fields := range structFields {
+		rc.structFields[keyStruct] = fields
+	}
+
+	for keyParameter, value := range parameters {
+		rc.parameters[keyParameter] = value
+	}
+
+	for keyDecorator, serviceDecorators := range decorators {
+		rc.decorators[keyDecorator] = append(rc.decorators[keyDecorator], serviceDecorators...)
+	}
+
+	for tag, taggedServices := range tags {
+		rc.tags[tag] = append(rc.tags[tag], taggedServices...)
+	}
+
+	for keySource, source := range sources {
+		rc.sources[keySource] = source
+	}
+
+	for keyLifetime, lifetime := range lifetimes {
+		rc.lifetimes[keyLifetime] = lifetime
+	}
+
+	for keyCache, cachedService := range cache {
+		rc.cache[keyCache] = cachedService
 	}
 
 	return rc.eventsContainer.merge(c.getEventsContainer())
@@ -252,16 +536,27 @@ func (rc *RuntimeContainer) AddGarbageCollectFunc(serviceName string, gcFunc Gar
 	rc.garbageCollectors.Add(serviceName, gcFunc)
 }
 
-//CollectGarbage will call all registered garbage collection functions and return the aggregated error result
+//CollectGarbage will call all registered garbage collection functions of created services and return the aggregated
+//error result, services which were never created are skipped
 func (rc *RuntimeContainer) CollectGarbage() error {
+	if rc.parent != nil {
+		return rc.collectScopeGarbage()
+	}
+
 	errs := []string{}
 	rc.garbageCollectors.Range(func(gcName string, gcFunc GarbageCollectorFunc) bool {
-		service, err := rc.GetSecure(gcName, true)
-		if err != nil {
-			errs = append(errs, err.Error())
+		//services which were never created have nothing to release, so they aren't created just to be destroyed
+		rc.mutex.RLock()
+		service, isCreated := rc.cache.Get(gcName)
+		rc.mutex.RUnlock()
+		if !isCreated {
+			if !rc.Exists(gcName) {
+				errs = append(errs, (&UnknownServiceError{ServiceID: gcName}).Error())
+			}
+			return true
 		}
 
-		err = gcFunc(service)
+		err := gcFunc(service)
 		if err != nil {
 			errs = append(errs, err.Error())
 		}
@@ -276,32 +571,178 @@ func (rc *RuntimeContainer) CollectGarbage() error {
 	return fmt.Errorf("Garbage collection errors: %s", strings.Join(errs, ", "))
 }
 
-//getConstructors exposes constructors for merge
+//getConstructors exposes a copy of constructors for merge
 func (rc *RuntimeContainer) getConstructors() map[string]Constructor {
-	return rc.constructors
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	constructors := make(map[string]Constructor, len(rc.constructors))
+	for id, constructor := range rc.constructors {
+		constructors[id] = constructor
+	}
+
+	return constructors
 }
 
-//getConstructors exposes new func constructors for merge
+//getNewFuncConstructors exposes a copy of new func constructors for merge
 func (rc *RuntimeContainer) getNewFuncConstructors() map[string]NewFuncConstructor {
-	return rc.newFuncConstructors
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	newFuncConstructors := make(map[string]NewFuncConstructor, len(rc.newFuncConstructors))
+	for id, newFuncConstructor := range rc.newFuncConstructors {
+		newFuncConstructors[id] = newFuncConstructor
+	}
+
+	return newFuncConstructors
+}
+
+//getServiceTypes exposes a copy of declared service types for merge
+func (rc *RuntimeContainer) getServiceTypes() map[string]reflect.Type {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	serviceTypes := make(map[string]reflect.Type, len(rc.serviceTypes))
+	for id, serviceType := range rc.serviceTypes {
+		serviceTypes[id] = serviceType
+	}
+
+	return serviceTypes
+}
+
+//getLifetimes exposes a copy of declared service lifetimes for merge
+func (rc *RuntimeContainer) getLifetimes() map[string]Lifetime {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	lifetimes := make(map[string]Lifetime, len(rc.lifetimes))
+	for id, lifetime := range rc.lifetimes {
+		lifetimes[id] = lifetime
+	}
+
+	return lifetimes
+}
+
+//getNewMethods exposes a copy of New methods declarations for merge
+func (rc *RuntimeContainer) getNewMethods() map[string]*newMethodDeclaration {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	newMethods := make(map[string]*newMethodDeclaration, len(rc.newMethods))
+	for id, newMethod := range rc.newMethods {
+		newMethods[id] = newMethod
+	}
+
+	return newMethods
+}
+
+//getStructFields exposes a copy of injected fields of struct services for merge
+func (rc *RuntimeContainer) getStructFields() map[string][]injectedField {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	structFields := make(map[string][]injectedField, len(rc.structFields))
+	for id, fields := range rc.structFields {
+		structFields[id] = fields
+	}
+
+	return structFields
+}
+
+//getParameters exposes a copy of parameters for merge
+func (rc *RuntimeContainer) getParameters() map[string]interface{} {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	parameters := make(map[string]interface{}, len(rc.parameters))
+	for name, value := range rc.parameters {
+		parameters[name] = value
+	}
+
+	return parameters
 }
 
-//getCache exposes cache for merge
+//getDecorators exposes a copy of decorators for merge
+func (rc *RuntimeContainer) getDecorators() map[string][]*serviceDecorator {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	decorators := make(map[string][]*serviceDecorator, len(rc.decorators))
+	for id, serviceDecorators := range rc.decorators {
+		decorators[id] = append([]*serviceDecorator{}, serviceDecorators...)
+	}
+
+	return decorators
+}
+
+//getSources exposes a copy of constructors sources for merge
+func (rc *RuntimeContainer) getSources() map[string]uintptr {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	sources := make(map[string]uintptr, len(rc.sources))
+	for id, source := range rc.sources {
+		sources[id] = source
+	}
+
+	return sources
+}
+
+//getCache exposes a copy of cache for merge
 func (rc *RuntimeContainer) getCache() dependencyCache {
-	return rc.cache
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	cache := newDependencyCache()
+	for id, service := range rc.cache {
+		cache.Set(id, service)
+	}
+
+	return cache
 }
 
 //getEventsContainer exposes events for merge
-func (rc *RuntimeContainer) getEventsContainer() EventsContainer {
-	return *rc.eventsContainer
+func (rc *RuntimeContainer) getEventsContainer() *EventsContainer {
+	return rc.eventsContainer
+}
+
+//assertValidID rejects ids which would be taken for references to tagged services or optional dependencies
+//when they are requested
+func assertValidID(id string) error {
+	if _, isTagReference := parseTagReference(id); isTagReference {
+		return fmt.Errorf(
+			"Cannot declare the service '%s' as the '%s' prefix is reserved for references to tagged services",
+			id,
+			tagReferencePrefix,
+		)
+	}
+
+	if strings.HasPrefix(id, optionalDependencyPrefix) {
+		return fmt.Errorf(
+			"Cannot declare the service '%s' as the '%s' prefix is reserved for optional dependencies",
+			id,
+			optionalDependencyPrefix,
+		)
+	}
+
+	if strings.Contains(id, defaultDependencySeparator) {
+		return fmt.Errorf(
+			"Cannot declare the service '%s' as '%s' is reserved for default values of optional dependencies",
+			id,
+			defaultDependencySeparator,
+		)
+	}
+
+	return nil
 }
 
-//assertNoDuplicates checks if current dependency was not already declared
+//assertNoDuplicates checks if current dependency was not already declared, must be called under the mutex
 func (rc *RuntimeContainer) assertNoDuplicates(id string) error {
 	_, constructorExists := rc.constructors[id]
 	_, newFuncExists := rc.newFuncConstructors[id]
+	_, aliasExists := rc.aliases[id]
 
-	if constructorExists || newFuncExists {
+	if constructorExists || newFuncExists || aliasExists {
 		return fmt.Errorf("Detected duplicated dependency declaration '%s'", id)
 	}
 
@@ -0,0 +1,86 @@
+package container
+
+import (
+	"fmt"
+	"strings"
+)
+
+//NewScope creates a child container e.g. for a single HTTP request. The scope sees all services of its parent:
+//singletons and services with the default lifetime are taken from the parent, while services with the Scoped lifetime
+//are cached in the scope and released with its CollectGarbage. Services declared directly in the scope are visible
+//only there
+func (rc *RuntimeContainer) NewScope() *RuntimeContainer {
+	scope := NewRuntimeContainer()
+	scope.parent = rc
+	scope.waitsMutex = rc.waitsMutex
+
+	return scope
+}
+
+//findOwner gives the closest container in the scopes chain where the service is declared or nil if it's unknown
+func (rc *RuntimeContainer) findOwner(id string) *RuntimeContainer {
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.mutex.RLock()
+		_, isConstructor := cont.constructors[id]
+		_, isNewFunc := cont.newFuncConstructors[id]
+		cont.mutex.RUnlock()
+
+		if isConstructor || isNewFunc {
+			return cont
+		}
+	}
+
+	return nil
+}
+
+//collectDependencyEvents notifies the created service about dependencies registered in the scopes chain
+func (rc *RuntimeContainer) collectDependencyEvents(r *resolution, id string, service interface{}) error {
+	errs := []error{}
+	for cont := rc; cont != nil; cont = cont.parent {
+		err := cont.eventsContainer.collectDependencyEventsForService(r, id, service, rc.notifyEventDispatched)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return mergeErrors(errs)
+}
+
+//collectScopeGarbage calls garbage collection functions only for services created in the scope and clears its cache,
+//services which were never created in the scope are skipped
+func (rc *RuntimeContainer) collectScopeGarbage() error {
+	rc.mutex.Lock()
+	cache := rc.cache
+	rc.cache = newDependencyCache()
+	rc.creationNumbers = make(map[string]uint64)
+	rc.mutex.Unlock()
+
+	errs := []string{}
+	knownGcNames := map[string]bool{}
+	for cont := rc; cont != nil; cont = cont.parent {
+		cont.garbageCollectors.Range(func(gcName string, gcFunc GarbageCollectorFunc) bool {
+			if knownGcNames[gcName] {
+				return true
+			}
+			knownGcNames[gcName] = true
+
+			service, isCreated := cache.Get(gcName)
+			if !isCreated {
+				return true
+			}
+
+			err := gcFunc(service)
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+
+			return true
+		})
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("Garbage collection errors: %s", strings.Join(errs, ", "))
+}
@@ -0,0 +1,173 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func createContainerWithScopedServices() *RuntimeContainer {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("db", func() *mocks.FakeDb {
+		return mocks.NewFakeDb("someConnectionString")
+	})
+	cont.SetLifetime("db", Singleton)
+	cont.AddGarbageCollectFunc("db", func(service interface{}) error {
+		return service.(*mocks.FakeDb).Destroy()
+	})
+
+	cont.AddNewMethod("transaction", func() *mocks.FakeDb {
+		return mocks.NewFakeDb("someConnectionString")
+	})
+	cont.SetLifetime("transaction", Scoped)
+	cont.AddGarbageCollectFunc("transaction", func(service interface{}) error {
+		return service.(*mocks.FakeDb).Destroy()
+	})
+
+	cont.AddNewMethod("book_storage", mocks.NewBookStorage, "transaction")
+	cont.SetLifetime("book_storage", Transient)
+
+	cont.AddNewMethod("book_shelve", mocks.NewBookShelve)
+	cont.SetLifetime("book_shelve", Scoped)
+
+	return cont
+}
+
+func TestScopesShareSingletons(t *testing.T) {
+	cont := createContainerWithScopedServices()
+	scope1 := cont.NewScope()
+	scope2 := cont.NewScope()
+
+	db := cont.Get("db", true)
+	if scope1.Get("db", true) != db || scope2.Get("db", true) != db {
+		t.Error("Scopes should share the singleton 'db' with the parent container")
+	}
+}
+
+func TestScopedServicesAreCachedPerScope(t *testing.T) {
+	cont := createContainerWithScopedServices()
+	scope1 := cont.NewScope()
+	scope2 := cont.NewScope()
+
+	shelve1 := scope1.Get("book_shelve", true)
+	if scope1.Get("book_shelve", false) != shelve1 {
+		t.Error("Scoped 'book_shelve' should be the same instance within a scope")
+	}
+
+	if scope2.Get("book_shelve", true) == shelve1 {
+		t.Error("Scoped 'book_shelve' should be a new instance in another scope")
+	}
+
+	if cont.Get("book_shelve", true) == shelve1 {
+		t.Error("Scoped 'book_shelve' of the parent container should differ from the scope instance")
+	}
+}
+
+func TestTransientServicesUseScopedDependencies(t *testing.T) {
+	cont := createContainerWithScopedServices()
+	scope := cont.NewScope()
+
+	scope.Get("book_storage", true)
+	transaction := scope.Get("transaction", true).(*mocks.FakeDb)
+
+	err := scope.CollectGarbage()
+	assertNoError(err, t)
+
+	if !transaction.WasDestroyed() {
+		t.Error("Scoped 'transaction' should be destroyed with the scope")
+	}
+
+	if cont.Get("db", true).(*mocks.FakeDb).WasDestroyed() {
+		t.Error("Singleton 'db' should not be destroyed with the scope")
+	}
+}
+
+func TestSharedServicesCannotCaptureScopedDependencies(t *testing.T) {
+	cont := createContainerWithScopedServices()
+	cont.AddNewMethod("shared_storage", mocks.NewBookStorage, "transaction")
+	cont.AddNewMethod("singleton_storage", mocks.NewBookStorage, "transaction")
+	cont.SetLifetime("singleton_storage", Singleton)
+	scope := cont.NewScope()
+
+	for _, id := range []string{"shared_storage", "singleton_storage"} {
+		_, err := scope.GetSecure(id, true)
+		assertErrorText(
+			"The scoped service 'transaction' cannot be a dependency of a service shared between scopes "+
+				"[check '"+id+"' service]",
+			err,
+			t,
+		)
+	}
+
+	//without scopes a scoped service is a singleton, so it can be used by any service
+	_, err := cont.GetSecure("singleton_storage", true)
+	assertNoError(err, t)
+}
+
+func TestScopeGarbageCollectionSkipsNotCreatedServices(t *testing.T) {
+	transactionsCount := 0
+	cont := NewRuntimeContainer()
+	cont.AddConstructor("transaction", func(c Container) (interface{}, error) {
+		transactionsCount++
+		return mocks.NewFakeDb("someConnectionString"), nil
+	})
+	cont.SetLifetime("transaction", Scoped)
+	cont.AddGarbageCollectFunc("transaction", func(service interface{}) error {
+		return service.(*mocks.FakeDb).Destroy()
+	})
+
+	scope := cont.NewScope()
+	err := scope.CollectGarbage()
+	assertNoError(err, t)
+
+	if transactionsCount != 0 {
+		t.Error("Garbage collection of a scope should not create services")
+	}
+
+	transaction := scope.Get("transaction", true).(*mocks.FakeDb)
+	err = scope.CollectGarbage()
+	assertNoError(err, t)
+
+	if scope.Get("transaction", true) == transaction {
+		t.Error("A new 'transaction' should be created after the scope garbage collection")
+	}
+}
+
+func TestServicesDeclaredInScope(t *testing.T) {
+	cont := createContainerWithScopedServices()
+	scope := cont.NewScope()
+
+	err := scope.AddConstructor("user_id", func(c Container) (interface{}, error) {
+		return "user_1", nil
+	})
+	assertNoError(err, t)
+
+	if scope.Get("user_id", true).(string) != "user_1" {
+		t.Error("A service declared in the scope should be available in it")
+	}
+
+	_, err = cont.GetSecure("user_id", true)
+	assertErrorText("Unknown dependency 'user_id'", err, t)
+
+	if !scope.Exists("user_id") || cont.Exists("user_id") {
+		t.Error("A service declared in the scope should exist only in the scope")
+	}
+}
+
+func TestConcurrentScopes(t *testing.T) {
+	cont := createContainerWithScopedServices()
+	db := cont.Get("db", true)
+
+	runConcurrently(goroutinesCount, func(i int) {
+		scope := cont.NewScope()
+		_, err := scope.GetSecure("book_storage", true)
+		assertNoError(err, t)
+
+		if scope.Get("db", true) != db {
+			t.Error("Scopes should share the singleton 'db' with the parent container")
+		}
+
+		err = scope.CollectGarbage()
+		assertNoError(err, t)
+	})
+}
@@ -0,0 +1,24 @@
+package container
+
+import (
+	"fmt"
+)
+
+//inFlightCall is a cached service construction which is currently in progress, concurrent requests for the same
+//service wait for it rather than creating the service once again
+type inFlightCall struct {
+	done    chan struct{}
+	owner   *resolutionState
+	service interface{}
+	err     error
+}
+
+func newInFlightCall(owner *resolutionState, serviceID string) *inFlightCall {
+	call := &inFlightCall{
+		done:  make(chan struct{}),
+		owner: owner,
+		err:   fmt.Errorf("Construction of '%s' was interrupted by a panic", serviceID),
+	}
+
+	return call
+}
@@ -0,0 +1,43 @@
+//go:build go1.21
+
+package container
+
+import (
+	"log/slog"
+	"time"
+)
+
+//SlogResolutionHook writes resolutions of services and dispatched events to a structured logger, failed resolutions
+//are logged as errors and the rest as debug messages
+type SlogResolutionHook struct {
+	logger *slog.Logger
+}
+
+//NewSlogResolutionHook creates a hook which writes to the logger or to the default logger if nil is given
+func NewSlogResolutionHook(logger *slog.Logger) *SlogResolutionHook {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SlogResolutionHook{logger: logger}
+}
+
+//OnResolveStart logs the start of the service resolution
+func (sh *SlogResolutionHook) OnResolveStart(id string) {
+	sh.logger.Debug("Resolving service", "service", id)
+}
+
+//OnResolveEnd logs the resolved service or the resolution error
+func (sh *SlogResolutionHook) OnResolveEnd(id string, duration time.Duration, err error, fromCache bool) {
+	if err != nil {
+		sh.logger.Error("Service resolution failed", "service", id, "duration", duration, "error", err)
+		return
+	}
+
+	sh.logger.Debug("Service resolved", "service", id, "duration", duration, "from_cache", fromCache)
+}
+
+//OnEventDispatched logs the dependency provided to the observer
+func (sh *SlogResolutionHook) OnEventDispatched(event, observer, dependency string) {
+	sh.logger.Debug("Dependency event dispatched", "event", event, "observer", observer, "dependency", dependency)
+}
@@ -0,0 +1,62 @@
+//go:build go1.21
+
+package container
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func TestSlogResolutionHook(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey || attr.Key == "duration" {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("cache", mocks.NewInMemoryCache)
+	cont.AddConstructor("failing_cache", func(c Container) (interface{}, error) {
+		return nil, errors.New("Cache is not available")
+	})
+	cont.AddNewMethod("statistics_gateway", mocks.NewStatisticsGateway)
+	cont.AddDependencyObserver("statistics_provider", "statistics_gateway", func(sg *mocks.StatisticsGateway, sp mocks.StatisticsProvider) {
+		sg.AddStatisticsProvider(sp)
+	})
+	cont.AddNewMethod("pg_stats", func() mocks.StatisticsProvider { return namedStatistics("pg_stats") })
+	cont.RegisterDependencyEvent("statistics_provider", "pg_stats")
+	cont.AddResolutionHook(NewSlogResolutionHook(logger))
+
+	cont.Get("cache", true)
+	_, err := cont.GetSecure("failing_cache", true)
+	cont.Get("statistics_gateway", true)
+
+	expectedLog := strings.Join([]string{
+		`level=DEBUG msg="Resolving service" service=cache`,
+		`level=DEBUG msg="Service resolved" service=cache from_cache=false`,
+		`level=DEBUG msg="Resolving service" service=failing_cache`,
+		//errors are logged with the resolution trace
+		fmt.Sprintf(`level=ERROR msg="Service resolution failed" service=failing_cache error=%q`, fmt.Sprintf("%+v", err)),
+		`level=DEBUG msg="Resolving service" service=statistics_gateway`,
+		`level=DEBUG msg="Resolving service" service=pg_stats`,
+		`level=DEBUG msg="Service resolved" service=pg_stats from_cache=false`,
+		`level=DEBUG msg="Dependency event dispatched" event=statistics_provider observer=statistics_gateway ` +
+			`dependency=pg_stats`,
+		`level=DEBUG msg="Service resolved" service=statistics_gateway from_cache=false`,
+		``,
+	}, "\n")
+	if buffer.String() != expectedLog {
+		t.Errorf("Log\n%s\nis expected, but\n%s\nis given", expectedLog, buffer.String())
+	}
+}
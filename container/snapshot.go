@@ -0,0 +1,126 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+)
+
+//Snapshot is a copy of the container state: declarations of services, aliases, tags, decorators, lifetimes,
+//cached services, events, observers, garbage collectors and hooks. It doesn't share maps with the container, so
+//changes of the container don't affect the snapshot and it can be restored several times
+type Snapshot struct {
+	owner               *RuntimeContainer
+	constructors        map[string]Constructor
+	newFuncConstructors map[string]NewFuncConstructor
+	serviceTypes        map[string]reflect.Type
+	newMethods          map[string]*newMethodDeclaration
+	structFields        map[string][]injectedField
+	parameters          map[string]interface{}
+	decorators          map[string][]*serviceDecorator
+	tags                map[string][]TaggedService
+	aliases             map[string]string
+	bindings            map[reflect.Type]string
+	sources             map[string]uintptr
+	lifetimes           map[string]Lifetime
+	cache               dependencyCache
+	creationNumbers     map[string]uint64
+	eventsContainer     *EventsContainer
+	garbageCollectors   *GarbageCollectorFuncs
+	lifecycleHooks      *lifecycleHooks
+	resolutionHooks     []ResolutionHook
+	isFrozen            bool
+}
+
+//Snapshot captures the current state of the container, e.g. to try changes in a test or a REPL and to roll them back
+//with Restore. Services created while the snapshot is taken might be missing in its cache
+func (rc *RuntimeContainer) Snapshot() *Snapshot {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	return &Snapshot{
+		owner:               rc,
+		constructors:        copyMap(rc.constructors),
+		newFuncConstructors: copyMap(rc.newFuncConstructors),
+		serviceTypes:        copyMap(rc.serviceTypes),
+		newMethods:          copyMap(rc.newMethods),
+		structFields:        copyMap(rc.structFields),
+		parameters:          copyMap(rc.parameters),
+		decorators:          copyDecorators(rc.decorators),
+		tags:                copyTags(rc.tags),
+		aliases:             copyMap(rc.aliases),
+		bindings:            copyMap(rc.bindings),
+		sources:             copyMap(rc.sources),
+		lifetimes:           copyMap(rc.lifetimes),
+		cache:               copyMap(rc.cache),
+		creationNumbers:     copyMap(rc.creationNumbers),
+		eventsContainer:     rc.eventsContainer.copy(),
+		garbageCollectors:   rc.garbageCollectors.copy(),
+		lifecycleHooks:      rc.lifecycleHooks.copy(),
+		resolutionHooks:     append([]ResolutionHook{}, rc.resolutionHooks...),
+		isFrozen:            rc.isFrozen,
+	}
+}
+
+//Restore brings the container back to the state captured by the snapshot of the same container, services created
+//after the snapshot was taken are dropped from the cache without garbage collection, scopes of the container are
+//not changed
+func (rc *RuntimeContainer) Restore(snapshot *Snapshot) error {
+	if snapshot == nil || snapshot.owner != rc {
+		return fmt.Errorf("Cannot restore the container from a snapshot of another container")
+	}
+
+	rc.mutex.Lock()
+	rc.constructors = copyMap(snapshot.constructors)
+	rc.newFuncConstructors = copyMap(snapshot.newFuncConstructors)
+	rc.serviceTypes = copyMap(snapshot.serviceTypes)
+	rc.newMethods = copyMap(snapshot.newMethods)
+	rc.structFields = copyMap(snapshot.structFields)
+	rc.parameters = copyMap(snapshot.parameters)
+	rc.decorators = copyDecorators(snapshot.decorators)
+	rc.tags = copyTags(snapshot.tags)
+	rc.aliases = copyMap(snapshot.aliases)
+	rc.bindings = copyMap(snapshot.bindings)
+	rc.sources = copyMap(snapshot.sources)
+	rc.lifetimes = copyMap(snapshot.lifetimes)
+	rc.cache = copyMap(snapshot.cache)
+	rc.creationNumbers = copyMap(snapshot.creationNumbers)
+	rc.resolutionHooks = append([]ResolutionHook{}, snapshot.resolutionHooks...)
+	rc.isFrozen = snapshot.isFrozen
+	rc.mutex.Unlock()
+
+	rc.eventsContainer.replace(snapshot.eventsContainer)
+	rc.garbageCollectors.replace(snapshot.garbageCollectors)
+	rc.lifecycleHooks.replace(snapshot.lifecycleHooks)
+
+	return nil
+}
+
+func copyMap[K comparable, V any](source map[K]V) map[K]V {
+	target := make(map[K]V, len(source))
+	for key, value := range source {
+		target[key] = value
+	}
+
+	return target
+}
+
+func copyDecorators(decorators map[string][]*serviceDecorator) map[string][]*serviceDecorator {
+	decoratorsCopy := make(map[string][]*serviceDecorator, len(decorators))
+	for id, serviceDecorators := range decorators {
+		decoratorsCopy[id] = append([]*serviceDecorator{}, serviceDecorators...)
+	}
+
+	return decoratorsCopy
+}
+
+func copyTags(tags map[string][]TaggedService) map[string][]TaggedService {
+	tagsCopy := make(map[string][]TaggedService, len(tags))
+	for tag, taggedServices := range tags {
+		for _, taggedService := range taggedServices {
+			taggedService.Attributes = copyMap(taggedService.Attributes)
+			tagsCopy[tag] = append(tagsCopy[tag], taggedService)
+		}
+	}
+
+	return tagsCopy
+}
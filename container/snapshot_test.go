@@ -0,0 +1,82 @@
+package container
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	cont := createContainerForOverride()
+	collectedServices := []string{}
+	cont.AddGarbageCollectFunc("db", func(service interface{}) error {
+		collectedServices = append(collectedServices, "db")
+		return nil
+	})
+	originalStorage := cont.Get("book_storage", true)
+	originalCache := cont.Get("cache", true)
+
+	snapshot := cont.Snapshot()
+
+	cont.SetNewMethod("cache", mocks.NewInMemoryCache)
+	cont.AddNewMethod("pg_stats", func() mocks.StatisticsProvider { return namedStatistics("pg_stats") })
+	cont.AddTag("pg_stats", "stats_provider")
+	cont.RegisterDependencyEvent("statistics_provider", "pg_stats")
+	cont.Alias("stats", "pg_stats")
+	cont.SetLifetime("book_storage", Transient)
+	cont.AddGarbageCollectFunc("cache", func(service interface{}) error {
+		collectedServices = append(collectedServices, "cache")
+		return nil
+	})
+	cont.OnStart("pg_stats", func(ctx context.Context, service interface{}) error {
+		return nil
+	}, time.Second)
+	cont.AddResolutionHook(NewMetricsCollector())
+	assertNoError(cont.Freeze(), t)
+	cont.Get("cache", true)
+	cont.Get("statistics_gateway", true)
+
+	for i := 0; i < 2; i++ {
+		assertNoError(cont.Restore(snapshot), t)
+
+		assertIDs(
+			"authors_storage,book_storage,cache,db,statistics_gateway,stats_providers,storage",
+			cont.Services(),
+			t,
+		)
+		assertIDs("book_storage,cache,db", cont.Instantiated(), t)
+		if cont.IsFrozen() || len(cont.getResolutionHooks()) != 0 || len(cont.getStartServices()) != 0 {
+			t.Error("The container should not be frozen and should have no hooks")
+		}
+		if cont.Get("book_storage", true) != originalStorage || cont.Get("cache", true) != originalCache {
+			t.Error("Cached services should be restored")
+		}
+		AssertExpectedDependency(cont, "stats_providers", 1, t)
+
+		gateway := cont.Get("statistics_gateway", true).(*mocks.StatisticsGateway)
+		if len(gateway.CollectStatistics()) != 1 {
+			t.Errorf("Only the book storage should be provided by the event, but %v is given", gateway.CollectStatistics())
+		}
+
+		collectedServices = []string{}
+		assertNoError(cont.CollectGarbage(), t)
+		if len(collectedServices) != 1 || collectedServices[0] != "db" {
+			t.Errorf("Only the garbage collector of the db is expected, but %v is called", collectedServices)
+		}
+
+		assertNoError(cont.AddNewMethod("pg_stats", func() mocks.StatisticsProvider { return namedStatistics("pg_stats") }), t)
+	}
+}
+
+func TestSnapshotErrors(t *testing.T) {
+	cont := createContainerForOverride()
+	otherCont := createContainerForOverride()
+
+	err := cont.Restore(otherCont.Snapshot())
+	assertErrorText("Cannot restore the container from a snapshot of another container", err, t)
+
+	err = cont.Restore(nil)
+	assertErrorText("Cannot restore the container from a snapshot of another container", err, t)
+}
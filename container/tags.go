@@ -0,0 +1,235 @@
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	//tagReferencePrefix marks a dependency name which refers to all services with a tag, e.g. "#stats_provider"
+	tagReferencePrefix = "#"
+	//priorityTagAttribute defines the order of tagged services, services with a higher priority go first
+	priorityTagAttribute = "priority"
+)
+
+//TaggedService is a service marked with a tag, the tag can carry attributes
+type TaggedService struct {
+	ID         string
+	Tag        string
+	Priority   int
+	Attributes map[string]string
+}
+
+//parseTag reads a tag declaration like "stats_provider" or "stats_provider,priority=10,format=json"
+func parseTag(id, tag string) (TaggedService, error) {
+	parts := strings.Split(tag, ",")
+	taggedService := TaggedService{
+		ID:         id,
+		Tag:        strings.TrimSpace(parts[0]),
+		Attributes: map[string]string{},
+	}
+	if taggedService.Tag == "" || strings.HasPrefix(taggedService.Tag, tagReferencePrefix) {
+		return taggedService, fmt.Errorf("Wrong tag name in '%s' [check '%s' service]", tag, id)
+	}
+
+	for _, attribute := range parts[1:] {
+		keyValue := strings.SplitN(attribute, "=", 2)
+		if len(keyValue) != 2 || strings.TrimSpace(keyValue[0]) == "" {
+			return taggedService, fmt.Errorf(
+				"Wrong tag attribute '%s' in '%s', a key=value pair is expected [check '%s' service]",
+				attribute,
+				tag,
+				id,
+			)
+		}
+		taggedService.Attributes[strings.TrimSpace(keyValue[0])] = strings.TrimSpace(keyValue[1])
+	}
+
+	if priority, hasPriority := taggedService.Attributes[priorityTagAttribute]; hasPriority {
+		var err error
+		taggedService.Priority, err = strconv.Atoi(priority)
+		if err != nil {
+			return taggedService, fmt.Errorf(
+				"The priority of the tag '%s' should be an integer, '%s' is given [check '%s' service]",
+				taggedService.Tag,
+				priority,
+				id,
+			)
+		}
+	}
+
+	return taggedService, nil
+}
+
+//parseTagReference tells if the dependency name refers to tagged services and gives the tag
+func parseTagReference(dependencyName string) (string, bool) {
+	if !strings.HasPrefix(dependencyName, tagReferencePrefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(dependencyName, tagReferencePrefix), true
+}
+
+//AddTag marks the service identified by id, which is declared in the container, with a tag. The tag is declared as
+//"name" or "name,key=value,..." where the "priority" attribute defines the order of tagged services.
+//All services with the tag are injected as a slice or variadic arguments by the "#name" dependency
+func (rc *RuntimeContainer) AddTag(id, tag string) error {
+	taggedService, err := parseTag(id, tag)
+	if err != nil {
+		return err
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	err = rc.assertNotFrozen(id)
+	if err != nil {
+		return err
+	}
+
+	_, isConstructor := rc.constructors[id]
+	_, isNewFunc := rc.newFuncConstructors[id]
+	if !isConstructor && !isNewFunc {
+		return fmt.Errorf("Cannot tag the service '%s' as it is not declared in the container", id)
+	}
+
+	for i, existingTaggedService := range rc.tags[taggedService.Tag] {
+		if existingTaggedService.ID == id {
+			rc.tags[taggedService.Tag][i] = taggedService
+			return nil
+		}
+	}
+	rc.tags[taggedService.Tag] = append(rc.tags[taggedService.Tag], taggedService)
+
+	return nil
+}
+
+//GetTaggedServices gives services with the tag declared in the container and its parents ordered by priority,
+//services with the same priority go in the order of their tagging
+func (rc *RuntimeContainer) GetTaggedServices(tag string) []TaggedService {
+	chain := []*RuntimeContainer{}
+	for cont := rc; cont != nil; cont = cont.parent {
+		chain = append([]*RuntimeContainer{cont}, chain...)
+	}
+
+	taggedServices := []TaggedService{}
+	positions := map[string]int{}
+	for _, cont := range chain {
+		cont.mutex.RLock()
+		for _, taggedService := range cont.tags[tag] {
+			//a scope overrides the attributes of a tagged service of its parent
+			if position, isKnown := positions[taggedService.ID]; isKnown {
+				taggedServices[position] = taggedService
+				continue
+			}
+			positions[taggedService.ID] = len(taggedServices)
+			taggedServices = append(taggedServices, taggedService)
+		}
+		cont.mutex.RUnlock()
+	}
+
+	sort.SliceStable(taggedServices, func(i, j int) bool {
+		return taggedServices[i].Priority > taggedServices[j].Priority
+	})
+
+	return taggedServices
+}
+
+//getTagged creates all services with the tag within the resolution
+func (r *resolution) getTagged(tag string, isCached bool) (interface{}, error) {
+	taggedServices := r.GetTaggedServices(tag)
+	ids := make([]string, 0, len(taggedServices))
+	for _, taggedService := range taggedServices {
+		ids = append(ids, taggedService.ID)
+	}
+
+	getDependency := newDependenciesResolver(r, ids, isCached)
+	services := make([]interface{}, 0, len(ids))
+	errs := []error{}
+	for i := range ids {
+		service, err := getDependency(i)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		services = append(services, service)
+	}
+
+	if len(errs) > 0 {
+		return nil, mergeErrors(errs)
+	}
+
+	return services, nil
+}
+
+//getTaggedServicesArgument converts tagged services to a slice which can be used as the argument
+func getTaggedServicesArgument(
+	reflectedNewMethodArgument reflect.Type,
+	dependencyFromContainer interface{},
+	dependencyName,
+	serviceId string,
+) (reflect.Value, error) {
+	reflectedServices := reflect.ValueOf(dependencyFromContainer)
+	if reflectedServices.Type().AssignableTo(reflectedNewMethodArgument) {
+		return reflectedServices, nil
+	}
+
+	if reflectedNewMethodArgument.Kind() != reflect.Slice {
+		return reflect.Value{}, &TypeMismatchError{
+			ServiceID: serviceId,
+			Arg:       dependencyName,
+			Expected:  reflectedNewMethodArgument,
+			Provided:  reflectedServices.Type(),
+		}
+	}
+
+	services, err := getTaggedServicesArguments(
+		reflectedNewMethodArgument.Elem(),
+		dependencyFromContainer,
+		dependencyName,
+		serviceId,
+	)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return reflect.Append(reflect.MakeSlice(reflectedNewMethodArgument, 0, len(services)), services...), nil
+}
+
+//getTaggedServicesArguments checks that every tagged service can be used as the argument, e.g. of a variadic func
+func getTaggedServicesArguments(
+	reflectedNewMethodArgument reflect.Type,
+	dependencyFromContainer interface{},
+	dependencyName,
+	serviceId string,
+) ([]reflect.Value, error) {
+	services := dependencyFromContainer.([]interface{})
+	arguments := make([]reflect.Value, 0, len(services))
+	errs := []error{}
+	for _, service := range services {
+		argument, err := getValidDependencyArgument(reflectedNewMethodArgument, service, dependencyName, serviceId, false)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		arguments = append(arguments, argument)
+	}
+
+	return arguments, mergeErrors(errs)
+}
+
+//getTags exposes a copy of tags for merge
+func (rc *RuntimeContainer) getTags() map[string][]TaggedService {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	tags := make(map[string][]TaggedService, len(rc.tags))
+	for tag, taggedServices := range rc.tags {
+		tags[tag] = append([]TaggedService{}, taggedServices...)
+	}
+
+	return tags
+}
@@ -0,0 +1,295 @@
+package container
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+//namedStatistics is a statistics provider which gives its name as the metric
+type namedStatistics string
+
+func (ns namedStatistics) GetStatistics() (string, int) {
+	return string(ns), 1
+}
+
+//newStatisticsNames joins names of the statistics providers in the order of their injection
+func newStatisticsNames(providers ...mocks.StatisticsProvider) string {
+	names := []string{}
+	for _, provider := range providers {
+		name, _ := provider.GetStatistics()
+		names = append(names, name)
+	}
+
+	return strings.Join(names, ",")
+}
+
+func createContainerWithTaggedStatistics(t *testing.T) *RuntimeContainer {
+	cont := NewRuntimeContainer()
+	for _, id := range []string{"pg_stats", "redis_stats", "cache_stats"} {
+		statistics := namedStatistics(id)
+		assertNoError(cont.AddNewMethod(id, func() mocks.StatisticsProvider { return statistics }), t)
+	}
+
+	assertNoError(cont.AddTag("pg_stats", "stats_provider"), t)
+	assertNoError(cont.AddTag("redis_stats", "stats_provider,priority=10,format=json"), t)
+	assertNoError(cont.AddTag("cache_stats", "stats_provider"), t)
+
+	return cont
+}
+
+func TestTaggedServicesInSlice(t *testing.T) {
+	cont := createContainerWithTaggedStatistics(t)
+	err := cont.AddNewMethod("stats_names", func(providers []mocks.StatisticsProvider) string {
+		return newStatisticsNames(providers...)
+	}, "#stats_provider")
+	assertNoError(err, t)
+
+	AssertExpectedDependency(cont, "stats_names", "redis_stats,pg_stats,cache_stats", t)
+}
+
+func TestTaggedServicesInVariadicArguments(t *testing.T) {
+	cont := createContainerWithTaggedStatistics(t)
+	err := cont.AddNewMethod("stats_names", func(prefix string, providers ...mocks.StatisticsProvider) string {
+		return prefix + ":" + newStatisticsNames(providers...)
+	}, "stats_prefix", "#stats_provider")
+	assertNoError(err, t)
+	cont.AddConstructor("stats_prefix", func(c Container) (interface{}, error) {
+		return "stats", nil
+	})
+
+	AssertExpectedDependency(cont, "stats_names", "stats:redis_stats,pg_stats,cache_stats", t)
+
+	assertNoError(cont.Freeze(), t)
+	AssertExpectedDependency(cont, "stats_names", "stats:redis_stats,pg_stats,cache_stats", t)
+}
+
+func TestTaggedServicesInFrozenContainer(t *testing.T) {
+	cont := createContainerWithTaggedStatistics(t)
+	cont.AddNewMethod("stats_names", newStatisticsNamesFromSlice, "#stats_provider")
+	assertNoError(cont.Freeze(), t)
+
+	AssertExpectedDependency(cont, "stats_names", "redis_stats,pg_stats,cache_stats", t)
+
+	err := cont.AddTag("pg_stats", "other_provider")
+	assertErrorText("Cannot declare the service 'pg_stats' as the container is frozen", err, t)
+}
+
+func newStatisticsNamesFromSlice(providers []mocks.StatisticsProvider) string {
+	return newStatisticsNames(providers...)
+}
+
+func TestTaggedServicesInParallel(t *testing.T) {
+	cont := createContainerWithTaggedStatistics(t)
+	cont.SetParallelism(3)
+	cont.AddNewMethod("stats_names", newStatisticsNamesFromSlice, "#stats_provider")
+
+	AssertExpectedDependency(cont, "stats_names", "redis_stats,pg_stats,cache_stats", t)
+}
+
+func TestEmptyTag(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("stats_names", newStatisticsNamesFromSlice, "#stats_provider")
+
+	AssertExpectedDependency(cont, "stats_names", "", t)
+}
+
+func TestGetTaggedServices(t *testing.T) {
+	cont := createContainerWithTaggedStatistics(t)
+
+	services, err := cont.GetSecure("#stats_provider", true)
+	assertNoError(err, t)
+	if len(services.([]interface{})) != 3 {
+		t.Errorf("All tagged services are expected, but %v is given", services)
+	}
+
+	expectedTaggedServices := []TaggedService{
+		{
+			ID:         "redis_stats",
+			Tag:        "stats_provider",
+			Priority:   10,
+			Attributes: map[string]string{"priority": "10", "format": "json"},
+		},
+		{ID: "pg_stats", Tag: "stats_provider", Attributes: map[string]string{}},
+		{ID: "cache_stats", Tag: "stats_provider", Attributes: map[string]string{}},
+	}
+	taggedServices := cont.GetTaggedServices("stats_provider")
+	if !reflect.DeepEqual(taggedServices, expectedTaggedServices) {
+		t.Errorf("Expected tagged services %v, but %v is given", expectedTaggedServices, taggedServices)
+	}
+}
+
+func TestTaggedServicesInScope(t *testing.T) {
+	cont := createContainerWithTaggedStatistics(t)
+	cont.AddNewMethod("stats_names", newStatisticsNamesFromSlice, "#stats_provider")
+	cont.SetLifetime("stats_names", Scoped)
+
+	scope := cont.NewScope()
+	scope.AddNewMethod("request_stats", func() mocks.StatisticsProvider { return namedStatistics("request_stats") })
+	scope.AddNewMethod("pg_stats", func() mocks.StatisticsProvider { return namedStatistics("scoped_pg_stats") })
+	assertNoError(scope.AddTag("request_stats", "stats_provider,priority=5"), t)
+	assertNoError(scope.AddTag("pg_stats", "stats_provider,priority=20"), t)
+
+	AssertExpectedDependency(
+		scope,
+		"stats_names",
+		"scoped_pg_stats,redis_stats,request_stats,cache_stats",
+		t,
+	)
+	AssertExpectedDependency(cont, "stats_names", "redis_stats,pg_stats,cache_stats", t)
+}
+
+func TestTagErrors(t *testing.T) {
+	cont := createContainerWithTaggedStatistics(t)
+
+	err := cont.AddTag("unknown_stats", "stats_provider")
+	assertErrorText("Cannot tag the service 'unknown_stats' as it is not declared in the container", err, t)
+
+	err = cont.AddTag("pg_stats", ",priority=1")
+	assertErrorText("Wrong tag name in ',priority=1' [check 'pg_stats' service]", err, t)
+
+	err = cont.AddTag("pg_stats", "stats_provider,json")
+	assertErrorText(
+		"Wrong tag attribute 'json' in 'stats_provider,json', a key=value pair is expected [check 'pg_stats' service]",
+		err,
+		t,
+	)
+
+	err = cont.AddTag("pg_stats", "stats_provider,priority=high")
+	assertErrorText(
+		"The priority of the tag 'stats_provider' should be an integer, 'high' is given [check 'pg_stats' service]",
+		err,
+		t,
+	)
+
+	cont.AddNewMethod("stats_gateway", func(provider mocks.StatisticsProvider) string {
+		return ""
+	}, "#stats_provider")
+	_, err = cont.GetSecure("stats_gateway", true)
+	assertErrorText(
+		"Cannot use the provided dependency '#stats_provider' of type '[]interface {}' as "+
+			"'mocks.StatisticsProvider' in the Constr function call [check 'stats_gateway' service]",
+		err,
+		t,
+	)
+
+	cont.AddNewMethod("stats_db", func(providers []*mocks.FakeDb) int {
+		return len(providers)
+	}, "#stats_provider")
+	_, err = cont.GetSecure("stats_db", true)
+	assertErrorText(
+		"Cannot use the provided dependency '#stats_provider' of type 'container.namedStatistics' as "+
+			"'*mocks.FakeDb' in the Constr function call [check 'stats_db' service];\n"+
+			"Cannot use the provided dependency '#stats_provider' of type 'container.namedStatistics' as "+
+			"'*mocks.FakeDb' in the Constr function call [check 'stats_db' service];\n"+
+			"Cannot use the provided dependency '#stats_provider' of type 'container.namedStatistics' as "+
+			"'*mocks.FakeDb' in the Constr function call [check 'stats_db' service]",
+		err,
+		t,
+	)
+}
+
+func TestTagReferencesCannotBeDeclared(t *testing.T) {
+	cont := NewRuntimeContainer()
+	expectedError := "Cannot declare the service '#stats_provider' as the '#' prefix is reserved for references " +
+		"to tagged services"
+
+	err := cont.AddConstructor("#stats_provider", func(c Container) (interface{}, error) {
+		return nil, nil
+	})
+	assertErrorText(expectedError, err, t)
+
+	err = cont.AddNewMethod("#stats_provider", mocks.NewFakeDb, "connection_string")
+	assertErrorText(expectedError, err, t)
+
+	err = cont.AddStruct("#stats_provider", mocks.BookCreator{})
+	assertErrorText(expectedError, err, t)
+
+	err = cont.Alias("#stats_provider", "pg_stats")
+	assertErrorText(expectedError, err, t)
+
+	defer ExpectPanic(t, expectedError)
+	cont.SetConstructor("#stats_provider", func(c Container) (interface{}, error) {
+		return nil, nil
+	})
+}
+
+func TestTaggedServicesFromConfig(t *testing.T) {
+	tree := Tree{
+		Node{ID: "pg_stats", NewFunc: func() mocks.StatisticsProvider { return namedStatistics("pg_stats") }, Tags: []string{"stats_provider"}},
+		Node{ID: "redis_stats", NewFunc: func() mocks.StatisticsProvider { return namedStatistics("redis_stats") }, Tags: []string{"stats_provider,priority=1"}},
+		Node{ID: "stats_names", NewFunc: newStatisticsNamesFromSlice, ServiceNames: Services{"#stats_provider"}},
+	}
+
+	assertNoError(ValidateGraphSecure(tree), t)
+
+	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfigSecure(tree)
+	assertNoError(err, t)
+	AssertExpectedDependency(cont, "stats_names", "redis_stats,pg_stats", t)
+}
+
+func TestTagsValidation(t *testing.T) {
+	err := ValidateConfigSecure(Tree{
+		Node{Tags: []string{"stats_provider"}},
+		Node{ID: "pg_stats", NewFunc: func() mocks.StatisticsProvider { return nil }, Tags: []string{"stats_provider,priority"}},
+	})
+	assertErrorText(
+		"A new or constructor function are expected but none was declared see 'Node: {ID: ; ServiceNames: []; "+
+			"Event: {Name: ; Service: ;}; Observer: {Name: ; Event: ;}}';\n"+
+			"Tags should be defined with a non empty new func or constructor, see 'Node: {ID: ; ServiceNames: []; "+
+			"Event: {Name: ; Service: ;}; Observer: {Name: ; Event: ;}}';\n"+
+			"Wrong tag attribute 'priority' in 'stats_provider,priority', a key=value pair is expected [check 'pg_stats' service]",
+		err,
+		t,
+	)
+
+	err = ValidateGraphSecure(Tree{
+		Node{ID: "pg_stats", NewFunc: func() string { return "" }, Tags: []string{"stats_provider"}},
+		Node{ID: "stats_names", NewFunc: newStatisticsNamesFromSlice, ServiceNames: Services{"#stats_provider"}},
+		Node{ID: "stats_count", NewFunc: func(providers mocks.StatisticsProvider) int { return 0 }, ServiceNames: Services{"#stats_provider"}},
+	})
+	assertErrorText(
+		"Cannot use the provided dependency 'pg_stats' of type 'string' as 'mocks.StatisticsProvider' "+
+			"in the Constr function call [check 'stats_names' service];\n"+
+			"Cannot use the provided dependency '#stats_provider' of type '[]interface {}' as 'mocks.StatisticsProvider' "+
+			"in the Constr function call [check 'stats_count' service]",
+		err,
+		t,
+	)
+}
+
+func TestTaggedServicesCycle(t *testing.T) {
+	err := ValidateGraphSecure(Tree{
+		Node{ID: "stats_names", NewFunc: func(providers ...mocks.StatisticsProvider) mocks.StatisticsProvider {
+			return nil
+		}, ServiceNames: Services{"#stats_provider"}, Tags: []string{"stats_provider"}},
+	})
+	assertErrorText("Detected dependencies' cycle: stats_names->stats_names", err, t)
+}
+
+func TestTaggedServicesFromYaml(t *testing.T) {
+	registry := NewFuncRegistry()
+	registry.RegisterFunc("newPgStats", func() mocks.StatisticsProvider { return namedStatistics("pg_stats") })
+	registry.RegisterFunc("newRedisStats", func() mocks.StatisticsProvider { return namedStatistics("redis_stats") })
+	registry.RegisterFunc("newStatisticsNames", newStatisticsNamesFromSlice)
+
+	tree, err := LoadTree([]byte(`
+services:
+  - id: pg_stats
+    new: newPgStats
+    tags: [stats_provider]
+  - id: redis_stats
+    new: newRedisStats
+    tags: ["stats_provider,priority=1"]
+  - id: stats_names
+    new: newStatisticsNames
+    services: ["#stats_provider"]
+`), "services.yaml", registry)
+	assertNoError(err, t)
+
+	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfigSecure(tree)
+	assertNoError(err, t)
+	AssertExpectedDependency(cont, "stats_names", "redis_stats,pg_stats", t)
+}
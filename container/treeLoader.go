@@ -0,0 +1,270 @@
+package container
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+type serviceDefinition struct {
+	ID          string   `yaml:"id"`
+	New         string   `yaml:"new"`
+	Constructor string   `yaml:"constructor"`
+	Services    []string `yaml:"services"`
+	Autowire    bool     `yaml:"autowire"`
+	Lifetime    string   `yaml:"lifetime"`
+	GarbageFunc string   `yaml:"gc"`
+	Decorates   string   `yaml:"decorates"`
+	Tags        []string `yaml:"tags"`
+	AliasOf     string   `yaml:"alias_of"`
+}
+
+type eventDefinition struct {
+	Name    string `yaml:"name"`
+	Service string `yaml:"service"`
+}
+
+type observerDefinition struct {
+	Name     string `yaml:"name"`
+	Event    string `yaml:"event"`
+	Callback string `yaml:"callback"`
+}
+
+//treeLoader converts a parsed config file to a Tree remembering lines of nodes for error messages
+type treeLoader struct {
+	source    string
+	registry  *FuncRegistry
+	tree      Tree
+	nodeLines []int
+	errs      []error
+}
+
+//LoadTreeFromFile reads service definitions from a YAML or JSON file, see LoadTree
+func LoadTreeFromFile(path string, registry *FuncRegistry) (Tree, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Tree{}, err
+	}
+
+	return LoadTree(data, path, registry)
+}
+
+//LoadTree converts YAML or JSON service definitions to a Tree which can be used in the RuntimeContainerBuilder,
+//function names are resolved with the registry. The source is used in error messages as
+//"source:line: error text", so that a wrong definition can be found easily. A nil registry is an empty one, so every
+//referenced function is reported as unknown
+func LoadTree(data []byte, source string, registry *FuncRegistry) (Tree, error) {
+	if registry == nil {
+		registry = NewFuncRegistry()
+	}
+
+	document := yaml.Node{}
+	err := yaml.Unmarshal(data, &document)
+	if err != nil {
+		return Tree{}, fmt.Errorf("%s: %v", source, err)
+	}
+
+	loader := &treeLoader{source: source, registry: registry, tree: Tree{}}
+	if len(document.Content) == 0 {
+		return loader.tree, nil
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return Tree{}, loader.newError(root, "A mapping with parameters, services, events and observers is expected")
+	}
+
+	for i := 0; i < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case "parameters":
+			loader.loadParameters(value)
+		case "services":
+			loader.loadSequence(value, loader.loadService)
+		case "events":
+			loader.loadSequence(value, loader.loadEvent)
+		case "observers":
+			loader.loadSequence(value, loader.loadObserver)
+		default:
+			loader.addError(key, "Unknown section '%s', expected sections are parameters, services, events and observers", key.Value)
+		}
+	}
+
+	if len(loader.errs) == 0 {
+		loader.validate()
+	}
+
+	if len(loader.errs) > 0 {
+		return Tree{}, mergeErrors(loader.errs)
+	}
+
+	return loader.tree, nil
+}
+
+func (tl *treeLoader) loadParameters(value *yaml.Node) {
+	parameters := map[string]interface{}{}
+	err := value.Decode(&parameters)
+	if err != nil {
+		tl.addError(value, "Cannot read parameters: %v", err)
+		return
+	}
+
+	tl.addNode(Node{Parameters: parameters}, value)
+}
+
+func (tl *treeLoader) loadSequence(value *yaml.Node, loadItem func(item *yaml.Node)) {
+	if value.Kind != yaml.SequenceNode {
+		tl.addError(value, "A list is expected")
+		return
+	}
+
+	for _, item := range value.Content {
+		loadItem(item)
+	}
+}
+
+func (tl *treeLoader) loadService(item *yaml.Node) {
+	definition := serviceDefinition{}
+	if !tl.decodeItem(item, &definition, "id", "new", "constructor", "services", "autowire", "lifetime", "gc", "decorates", "tags", "alias_of") {
+		return
+	}
+
+	node := Node{
+		ID:           definition.ID,
+		ServiceNames: definition.Services,
+		Autowire:     definition.Autowire,
+		Decorates:    definition.Decorates,
+		Tags:         definition.Tags,
+		AliasOf:      definition.AliasOf,
+	}
+	if definition.ID == "" {
+		//errors of a decorator refer to the decorated service
+		definition.ID = definition.Decorates
+	}
+	var err error
+	if definition.New != "" {
+		node.NewFunc, err = tl.registry.GetFunc(definition.New)
+		tl.addServiceError(getField(item, "new"), definition.ID, err)
+	}
+
+	if definition.Constructor != "" {
+		node.Constr, err = tl.registry.getConstructor(definition.Constructor)
+		tl.addServiceError(getField(item, "constructor"), definition.ID, err)
+	}
+
+	if definition.GarbageFunc != "" {
+		node.GarbageFunc, err = tl.registry.getGarbageCollectorFunc(definition.GarbageFunc)
+		tl.addServiceError(getField(item, "gc"), definition.ID, err)
+	}
+
+	if definition.Lifetime != "" {
+		node.Lifetime, err = parseLifetime(definition.Lifetime)
+		tl.addServiceError(getField(item, "lifetime"), definition.ID, err)
+	}
+
+	tl.addNode(node, item)
+}
+
+func (tl *treeLoader) loadEvent(item *yaml.Node) {
+	definition := eventDefinition{}
+	if !tl.decodeItem(item, &definition, "name", "service") {
+		return
+	}
+
+	tl.addNode(Node{Ev: Event{Name: definition.Name, Service: definition.Service}}, item)
+}
+
+func (tl *treeLoader) loadObserver(item *yaml.Node) {
+	definition := observerDefinition{}
+	if !tl.decodeItem(item, &definition, "name", "event", "callback") {
+		return
+	}
+
+	node := Node{Ob: Observer{Name: definition.Name, Event: definition.Event}}
+	if definition.Callback != "" {
+		var err error
+		node.Ob.Callback, err = tl.registry.GetFunc(definition.Callback)
+		if err != nil {
+			tl.addError(getField(item, "callback"), "%v [check '%s' observer]", err, definition.Name)
+		}
+	}
+
+	tl.addNode(node, item)
+}
+
+//decodeItem decodes a mapping to the definition and reports fields which are not in the knownFields list
+func (tl *treeLoader) decodeItem(item *yaml.Node, definition interface{}, knownFields ...string) bool {
+	if item.Kind != yaml.MappingNode {
+		tl.addError(item, "A mapping is expected")
+		return false
+	}
+
+	isValid := true
+	for i := 0; i < len(item.Content); i += 2 {
+		key := item.Content[i]
+		if !isStringInSlice(key.Value, knownFields) {
+			tl.addError(key, "Unknown field '%s'", key.Value)
+			isValid = false
+		}
+	}
+
+	err := item.Decode(definition)
+	if err != nil {
+		tl.addError(item, "%v", err)
+		isValid = false
+	}
+
+	return isValid
+}
+
+func (tl *treeLoader) addNode(node Node, value *yaml.Node) {
+	tl.tree = append(tl.tree, node)
+	tl.nodeLines = append(tl.nodeLines, value.Line)
+}
+
+//validate validates the loaded nodes one by one to prefix errors with lines of their definitions
+func (tl *treeLoader) validate() {
+	for i, node := range tl.tree {
+		nodeErrs := []error{}
+		validateNode(node, &nodeErrs, tl.tree)
+		for _, err := range nodeErrs {
+			tl.errs = append(tl.errs, fmt.Errorf("%s:%d: %v", tl.source, tl.nodeLines[i], err))
+		}
+	}
+}
+
+func (tl *treeLoader) addServiceError(value *yaml.Node, serviceID string, err error) {
+	if err != nil {
+		tl.addError(value, "%v [check '%s' service]", err, serviceID)
+	}
+}
+
+func (tl *treeLoader) addError(value *yaml.Node, format string, args ...interface{}) {
+	tl.errs = append(tl.errs, tl.newError(value, format, args...))
+}
+
+func (tl *treeLoader) newError(value *yaml.Node, format string, args ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s", tl.source, value.Line, fmt.Sprintf(format, args...))
+}
+
+//getField gives the value of a mapping field, so that errors point to its line
+func getField(item *yaml.Node, name string) *yaml.Node {
+	for i := 0; i < len(item.Content); i += 2 {
+		if item.Content[i].Value == name {
+			return item.Content[i+1]
+		}
+	}
+
+	return item
+}
+
+func isStringInSlice(needle string, haystack []string) bool {
+	for _, item := range haystack {
+		if item == needle {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,214 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+const yamlConfig = `
+parameters:
+  connection_string: someConnectionString
+  books_count: 3
+services:
+  - id: db
+    new: mocks.NewFakeDb
+    services: [connection_string]
+    lifetime: singleton
+    gc: destroyDb
+  - id: book_storage
+    new: mocks.NewBookStorage
+    autowire: true
+  - id: statistics_gateway
+    new: mocks.NewStatisticsGateway
+  - id: logger
+    constructor: newLogger
+events:
+  - name: add_stats_provider
+    service: book_storage
+observers:
+  - name: statistics_gateway
+    event: add_stats_provider
+    callback: addStatsProvider
+`
+
+const jsonConfig = `{
+  "parameters": {"connection_string": "someConnectionString"},
+  "services": [
+    {"id": "db", "new": "mocks.NewFakeDb", "services": ["connection_string"]},
+    {"id": "book_storage", "new": "mocks.NewBookStorage", "services": ["db"]}
+  ]
+}`
+
+func createFuncRegistry(t *testing.T) *FuncRegistry {
+	registry := NewFuncRegistry()
+	funcs := map[string]interface{}{
+		"mocks.NewFakeDb":            mocks.NewFakeDb,
+		"mocks.NewBookStorage":       mocks.NewBookStorage,
+		"mocks.NewStatisticsGateway": mocks.NewStatisticsGateway,
+		"newLogger": func(c Container) (interface{}, error) {
+			return mocks.NullLogger{}, nil
+		},
+		"destroyDb": func(service interface{}) error {
+			return service.(*mocks.FakeDb).Destroy()
+		},
+		"addStatsProvider": func(sg *mocks.StatisticsGateway, sp mocks.StatisticsProvider) {
+			sg.AddStatisticsProvider(sp)
+		},
+	}
+
+	for name, function := range funcs {
+		err := registry.RegisterFunc(name, function)
+		assertNoError(err, t)
+	}
+
+	return registry
+}
+
+func TestLoadTreeFromYaml(t *testing.T) {
+	tree, err := LoadTree([]byte(yamlConfig), "services.yaml", createFuncRegistry(t))
+	assertNoError(err, t)
+
+	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfigSecure(tree)
+	assertNoError(err, t)
+
+	booksCount := cont.Get("books_count", true).(int)
+	if booksCount != 3 {
+		t.Errorf("Parameter 'books_count' should be 3, but %d was returned", booksCount)
+	}
+
+	statisticsGateway := cont.Get("statistics_gateway", true).(*mocks.StatisticsGateway)
+	statistics := statisticsGateway.CollectStatistics()
+	if statistics["books_count"] != 2 {
+		t.Errorf("Statistics of 'book_storage' should be collected by the observer, but %v was returned", statistics)
+	}
+
+	cont.Get("logger", true)
+
+	db := cont.Get("db", false).(*mocks.FakeDb)
+	if db != cont.Get("db", false) {
+		t.Error("Singleton 'db' should be always taken from the cache")
+	}
+
+	err = cont.CollectGarbage()
+	assertNoError(err, t)
+	if !db.WasDestroyed() {
+		t.Error("Garbage collection function of 'db' should be called")
+	}
+}
+
+func TestLoadTreeFromJsonFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services.json")
+	err := os.WriteFile(path, []byte(jsonConfig), 0600)
+	assertNoError(err, t)
+
+	tree, err := LoadTreeFromFile(path, createFuncRegistry(t))
+	assertNoError(err, t)
+
+	cont, err := RuntimeContainerBuilder{}.BuildContainerFromConfigSecure(tree)
+	assertNoError(err, t)
+
+	var bookStorage mocks.BookStorage
+	cont.Scan("book_storage", &bookStorage)
+}
+
+func TestLoadTreeErrorsPointToLines(t *testing.T) {
+	config := `
+services:
+  - id: db
+    new: mocks.NewFakeDb
+  - id: book_storage
+    new: mocks.NewBookCreator
+    services: [db]
+  - id: book_shelve
+    new: mocks.NewStatisticsGateway
+    labels: [shelves]
+  - id: statistics_gateway
+    new: mocks.NewStatisticsGateway
+    lifetime: forever
+observers:
+  - name: statistics_gateway
+    event: add_stats_provider
+    callback: unknownCallback
+`
+	_, err := LoadTree([]byte(config), "services.yaml", createFuncRegistry(t))
+	assertErrorText(
+		"services.yaml:6: Unknown function 'mocks.NewBookCreator' [check 'book_storage' service];\n"+
+			"services.yaml:10: Unknown field 'labels';\n"+
+			"services.yaml:13: Unknown lifetime 'forever', expected lifetimes are default, singleton, transient and scoped [check 'statistics_gateway' service];\n"+
+			"services.yaml:17: Unknown function 'unknownCallback' [check 'statistics_gateway' observer]",
+		err,
+		t,
+	)
+}
+
+func TestLoadTreeValidationErrorsPointToLines(t *testing.T) {
+	config := `
+services:
+  - id: db
+    new: mocks.NewFakeDb
+  - id: logger
+    constructor: newLogger
+    services: [db]
+`
+	_, err := LoadTree([]byte(config), "services.yaml", createFuncRegistry(t))
+	assertErrorText(
+		"services.yaml:3: The function requires 1 arguments, but 0 arguments are provided [check 'Node: {ID: db; ServiceNames: []; Event: {Name: ; Service: ;}; Observer: {Name: ; Event: ;}}' service]",
+		err,
+		t,
+	)
+}
+
+func TestLoadTreeParsingErrors(t *testing.T) {
+	_, err := LoadTree([]byte("services: [id: db"), "services.yaml", NewFuncRegistry())
+	AssertError(err, "services.yaml: yaml: line 1: did not find expected ',' or ']'", t)
+
+	_, err = LoadTree([]byte("- id: db"), "services.yaml", NewFuncRegistry())
+	assertErrorText("services.yaml:1: A mapping with parameters, services, events and observers is expected", err, t)
+
+	_, err = LoadTree([]byte("services:\n  id: db\nhooks: []"), "services.yaml", NewFuncRegistry())
+	assertErrorText(
+		"services.yaml:2: A list is expected;\n"+
+			"services.yaml:3: Unknown section 'hooks', expected sections are parameters, services, events and observers",
+		err,
+		t,
+	)
+
+	_, err = LoadTreeFromFile(filepath.Join(t.TempDir(), "missing.yaml"), NewFuncRegistry())
+	if !os.IsNotExist(err) {
+		t.Errorf("A not existing file error is expected, but '%v' was returned", err)
+	}
+}
+
+func TestLoadTreeWithoutRegistry(t *testing.T) {
+	tree, err := LoadTree([]byte("parameters:\n  connection_string: someConnectionString"), "services.yaml", nil)
+	assertNoError(err, t)
+	if len(tree) != 1 {
+		t.Errorf("A tree with 1 node is expected, but %d nodes are given", len(tree))
+	}
+
+	_, err = LoadTree([]byte("services:\n  - id: db\n    new: mocks.NewFakeDb"), "services.yaml", nil)
+	assertErrorText("services.yaml:3: Unknown function 'mocks.NewFakeDb' [check 'db' service]", err, t)
+}
+
+func TestFuncRegistryErrors(t *testing.T) {
+	registry := NewFuncRegistry()
+
+	err := registry.RegisterFunc("mocks.FakeDb", mocks.FakeDb{})
+	assertErrorText("A function is expected but 'mocks.FakeDb' was provided for the name 'mocks.FakeDb'", err, t)
+
+	err = registry.RegisterFunc("", mocks.NewFakeDb)
+	assertErrorText("A non empty name is expected for the function 'func(string) *mocks.FakeDb'", err, t)
+
+	err = registry.RegisterFunc("mocks.NewFakeDb", mocks.NewFakeDb)
+	assertNoError(err, t)
+
+	_, err = registry.getConstructor("mocks.NewFakeDb")
+	assertErrorText("The function 'mocks.NewFakeDb' of type 'func(string) *mocks.FakeDb' cannot be used as a constructor", err, t)
+
+	if names := registry.Names(); len(names) != 1 || names[0] != "mocks.NewFakeDb" {
+		t.Errorf("Registered function names are unexpected: %v", names)
+	}
+}
@@ -0,0 +1,55 @@
+package container
+
+import "reflect"
+
+//Resolve fetches a cached Service identified by id as a value of type T,
+//returns TypeMismatchError if the Service is not of type T
+func Resolve[T any](c Container, id string) (T, error) {
+	return resolveTyped[T](c, id, true)
+}
+
+//ResolveNonCached creates a Service identified by id every time it is called and returns it as a value of type T
+func ResolveNonCached[T any](c Container, id string) (T, error) {
+	return resolveTyped[T](c, id, false)
+}
+
+//MustResolve does the same as Resolve but panics on failure
+func MustResolve[T any](c Container, id string) T {
+	service, err := Resolve[T](c, id)
+	if err != nil {
+		panic(err)
+	}
+
+	return service
+}
+
+//Provide registers a typed constructor of a Service identified by id, fails if id was already declared
+func Provide[T any](c Container, id string, constructor func(c Container) (T, error)) error {
+	return c.AddConstructor(id, func(c Container) (interface{}, error) {
+		return constructor(c)
+	})
+}
+
+func resolveTyped[T any](c Container, id string, isCached bool) (T, error) {
+	var typedService T
+	service, err := c.GetSecure(id, isCached)
+	if err != nil {
+		return typedService, err
+	}
+
+	expectedType := reflect.TypeOf(&typedService).Elem()
+	if service == nil && isNillable(expectedType) {
+		return typedService, nil
+	}
+
+	typedService, ok := service.(T)
+	if !ok {
+		return typedService, &TypeMismatchError{
+			ServiceID: id,
+			Expected:  expectedType,
+			Provided:  reflect.TypeOf(service),
+		}
+	}
+
+	return typedService, nil
+}
@@ -0,0 +1,140 @@
+package container
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/breathbath/gotainer/container/mocks"
+)
+
+func TestResolveTypedService(t *testing.T) {
+	cont := CreateContainer()
+
+	bookFinder, err := Resolve[mocks.BookFinder](cont, "book_finder")
+	assertNoError(err, t)
+
+	book, found := bookFinder.FindBook("one")
+	if !found || book.Title != "FirstBook" {
+		t.Error("Resolved book finder should find a book 'one'")
+	}
+
+	db, err := Resolve[*mocks.FakeDb](cont, "db")
+	assertNoError(err, t)
+	if db != cont.Get("db", true).(*mocks.FakeDb) {
+		t.Error("Resolve should return the cached instance of the 'db' service")
+	}
+}
+
+func TestResolveInterface(t *testing.T) {
+	cont := CreateContainer()
+
+	cache, err := Resolve[mocks.Cache](cont, "in_memory_cache")
+	assertNoError(err, t)
+	if _, ok := cache.(*mocks.InMemoryCache); !ok {
+		t.Errorf("Unexpected implementation '%T' of the cache interface is resolved", cache)
+	}
+}
+
+func TestResolveNonCached(t *testing.T) {
+	cont := CreateContainer()
+
+	bookShelve := MustResolve[*mocks.BookShelve](cont, "book_shelve")
+	bookShelve.Add(mocks.Book{Id: "123", Title: "Book1", Author: "Author1"})
+
+	nonCachedBookShelve, err := ResolveNonCached[*mocks.BookShelve](cont, "book_shelve")
+	assertNoError(err, t)
+	if len(nonCachedBookShelve.GetBooks()) != 0 {
+		t.Error("Book shelve should be empty if container is asked for uncached dependency")
+	}
+}
+
+func TestResolveNilService(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddConstructor("no_cache", func(c Container) (interface{}, error) {
+		return nil, nil
+	})
+
+	cache, err := Resolve[mocks.Cache](cont, "no_cache")
+	assertNoError(err, t)
+	if cache != nil {
+		t.Error("A nil cache is expected")
+	}
+
+	_, err = Resolve[mocks.Book](cont, "no_cache")
+	assertErrorText("Cannot use the service 'no_cache' of type '<nil>' as 'mocks.Book' [check 'no_cache' service]", err, t)
+}
+
+func TestResolveTypeMismatch(t *testing.T) {
+	cont := CreateContainer()
+
+	_, err := Resolve[mocks.BookCreator](cont, "wrong_book_creator")
+	assertErrorText(
+		"Cannot use the service 'wrong_book_creator' of type 'int' as 'mocks.BookCreator' [check 'wrong_book_creator' service]",
+		err,
+		t,
+	)
+
+	var typeMismatchError *TypeMismatchError
+	if !errors.As(err, &typeMismatchError) {
+		t.Fatalf("TypeMismatchError is expected but '%T' is returned", err)
+	}
+
+	if typeMismatchError.Provided != reflect.TypeOf(0) || typeMismatchError.Expected != reflect.TypeOf(mocks.BookCreator{}) {
+		t.Errorf("Wrong types in the type mismatch error: %v", typeMismatchError)
+	}
+}
+
+func TestResolveUnknownService(t *testing.T) {
+	cont := CreateContainer()
+
+	_, err := Resolve[mocks.BookCreator](cont, "lala")
+	assertErrorText("Unknown dependency 'lala'", err, t)
+}
+
+func TestMustResolvePanics(t *testing.T) {
+	defer ExpectPanic(t, "Cannot use the service 'config' of type 'mocks.Config' as 'mocks.Book' [check 'config' service]")
+
+	cont := CreateContainer()
+	MustResolve[mocks.Book](cont, "config")
+}
+
+func TestProvideTypedConstructor(t *testing.T) {
+	cont := NewRuntimeContainer()
+	err := Provide(cont, "connection_string", func(c Container) (string, error) {
+		return "someConnectionString", nil
+	})
+	assertNoError(err, t)
+
+	err = Provide(cont, "db", func(c Container) (*mocks.FakeDb, error) {
+		connectionString, err := Resolve[string](c, "connection_string")
+		if err != nil {
+			return nil, err
+		}
+
+		return mocks.NewFakeDb(connectionString), nil
+	})
+	assertNoError(err, t)
+
+	db, err := Resolve[*mocks.FakeDb](cont, "db")
+	assertNoError(err, t)
+	if db == nil {
+		t.Error("A db instance is expected")
+	}
+
+	err = Provide(cont, "db", func(c Container) (*mocks.FakeDb, error) {
+		return nil, nil
+	})
+	assertErrorText("Detected duplicated dependency declaration 'db'", err, t)
+}
+
+func TestProvideWithFailingConstructor(t *testing.T) {
+	cont := NewRuntimeContainer()
+	err := Provide(cont, "db", func(c Container) (*mocks.FakeDb, error) {
+		return nil, errors.New("Cannot connect to db")
+	})
+	assertNoError(err, t)
+
+	_, err = Resolve[*mocks.FakeDb](cont, "db")
+	assertErrorText("Cannot connect to db [check 'db' service]", err, t)
+}
@@ -7,19 +7,18 @@ import (
 //convertNewMethodToNewFuncConstructor creates a Callback that will call a New method of a Service with the Config
 //declared as newMethodArgumentNames.
 //Suppose we have func NewServiceA(sb ServiceB, sc ServiceC) ServiceA, if you call
-//convertNewMethodToNewFuncConstructor(container, NewServiceA, "service_b", "service_c"), you will get a Callback that will:
+//convertNewMethodToNewFuncConstructor(NewServiceA, []string{"service_b", "service_c"}, "service_a"), you will get a Callback that will:
 //a) fetch "service_b" and "service_c" from the container
 //b) validate if type of "service_b" and "service_c" is convertable to the NewServiceA arguments
 //Constr) call NewServiceA with the results of container.Get("service_b") and container.Get("service_c")
 func convertNewMethodToNewFuncConstructor(
-	container Container,
 	newMethod interface{},
 	newMethodArgumentNames []string,
 	serviceId string,
 ) (NewFuncConstructor, error) {
 	reflectedNewMethod := reflect.ValueOf(newMethod)
 
-	err := assertFunctionDeclaration(reflectedNewMethod, len(newMethodArgumentNames), serviceId)
+	err := assertNewMethodDeclaration(reflectedNewMethod, len(newMethodArgumentNames), serviceId)
 	if err != nil {
 		return nil, err
 	}
@@ -30,26 +29,54 @@ func convertNewMethodToNewFuncConstructor(
 	}
 
 	return func(c Container, isCached bool) (interface{}, error) {
-		argumentsToCallConstructorFunc, err := getValidFunctionArguments(
-			reflectedNewMethod,
-			newMethodArgumentNames,
-			container,
-			serviceId,
-			isCached,
+		return callNewMethod(reflectedNewMethod, newMethodArgumentNames, c, serviceId, isCached)
+	}, nil
+}
+
+//callNewMethod fetches dependencies declared as newMethodArgumentNames and calls the New method of a Service with them
+func callNewMethod(
+	reflectedNewMethod reflect.Value,
+	newMethodArgumentNames []string,
+	c Container,
+	serviceId string,
+	isCached bool,
+) (interface{}, error) {
+	argumentsToCallConstructorFunc, err := getValidFunctionArguments(
+		reflectedNewMethod,
+		newMethodArgumentNames,
+		c,
+		serviceId,
+		isCached,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if skippedContextArgumentsCount(reflectedNewMethod.Type(), len(newMethodArgumentNames)) > 0 {
+		argumentsToCallConstructorFunc = append(
+			[]reflect.Value{reflect.ValueOf(ContextOf(c))},
+			argumentsToCallConstructorFunc...,
 		)
-		if err != nil {
-			return nil, err
-		}
+	}
 
-		values := reflectedNewMethod.Call(argumentsToCallConstructorFunc)
-		if reflectedNewMethod.Type().NumOut() == 2 {
-			if isErrorType(reflectedNewMethod.Type().Out(0)) {
-				return collectErrorAndResult(values[0], values[1])
-			}
-			return collectErrorAndResult(values[1], values[0])
+	values := reflectedNewMethod.Call(argumentsToCallConstructorFunc)
+	if reflectedNewMethod.Type().NumOut() == 2 {
+		if isErrorType(reflectedNewMethod.Type().Out(0)) {
+			return collectErrorAndResult(values[0], values[1])
 		}
-		return values[0].Interface(), nil
-	}, nil
+		return collectErrorAndResult(values[1], values[0])
+	}
+	return values[0].Interface(), nil
+}
+
+//getNewMethodServiceType gives the declared type of a Service created by a valid New method
+func getNewMethodServiceType(reflectedNewMethod reflect.Value) reflect.Type {
+	newMethodType := reflectedNewMethod.Type()
+	if newMethodType.NumOut() == 2 && isErrorType(newMethodType.Out(0)) {
+		return newMethodType.Out(1)
+	}
+
+	return newMethodType.Out(0)
 }
 
 func collectErrorAndResult(reflectedErrorValue, reflectedServiceValue reflect.Value) (interface{}, error) {
@@ -132,37 +159,49 @@ func getValidFunctionArgumentsForVariadicFunc(
 	isCached bool,
 ) ([]reflect.Value, error) {
 	argumentsToCallNewMethod := []reflect.Value{}
-	constructorInputCount := reflectedNewMethod.Type().NumIn()
+	skippedInputCount := skippedContextArgumentsCount(reflectedNewMethod.Type(), len(newMethodArgumentNames))
+	constructorInputCount := reflectedNewMethod.Type().NumIn() - skippedInputCount
+	getDependency := newDependenciesResolver(container, newMethodArgumentNames, isCached)
 	var i = 0
 	var errors []error
 	for _, dependencyName := range newMethodArgumentNames {
 		i++
-		dependencyFromContainer, err := container.GetSecure(dependencyName, isCached)
+		dependencyFromContainer, err := getDependency(i - 1)
 		if err != nil {
-			return nil, err
+			errors = append(errors, err)
+			continue
 		}
 
-		reflectedDependencyFromContainer := reflect.ValueOf(dependencyFromContainer)
-
 		var reflectedNewMethodArgument reflect.Type
 		if i < constructorInputCount {
-			reflectedNewMethodArgument = reflectedNewMethod.Type().In(i - 1)
+			reflectedNewMethodArgument = reflectedNewMethod.Type().In(skippedInputCount + i - 1)
 		} else {
-			reflectedVariadicArgumentCollection := reflectedNewMethod.Type().In(constructorInputCount - 1)
+			reflectedVariadicArgumentCollection := reflectedNewMethod.Type().In(skippedInputCount + constructorInputCount - 1)
 			reflectedNewMethodArgument = reflectedVariadicArgumentCollection.Elem()
+
+			//tagged services are expanded to separate variadic arguments
+			if _, isTagReference := parseTagReference(dependencyName); isTagReference {
+				taggedArguments, err := getTaggedServicesArguments(
+					reflectedNewMethodArgument,
+					dependencyFromContainer,
+					dependencyName,
+					serviceId,
+				)
+				if err != nil {
+					errors = append(errors, err)
+					continue
+				}
+				argumentsToCallNewMethod = append(argumentsToCallNewMethod, taggedArguments...)
+				continue
+			}
 		}
 
-		reflectedDependencyFromContainer = replaceCompatibleNilDependency(
+		reflectedDependencyFromContainer, err := getValidFunctionArgument(
 			reflectedNewMethodArgument,
-			reflectedDependencyFromContainer,
 			dependencyFromContainer,
-		)
-
-		err = assertCompatible(
-			reflectedNewMethodArgument,
-			reflectedDependencyFromContainer.Type(),
 			dependencyName,
 			serviceId,
+			isParameterDependency(container, dependencyName),
 		)
 		if err != nil {
 			errors = append(errors, err)
@@ -194,42 +233,108 @@ func getValidFunctionArguments(
 		)
 	}
 
-	constructorInputCount := reflectedNewMethod.Type().NumIn()
+	skippedInputCount := skippedContextArgumentsCount(reflectedNewMethod.Type(), len(newMethodArgumentNames))
+	constructorInputCount := reflectedNewMethod.Type().NumIn() - skippedInputCount
 	argumentsToCallNewMethod := make([]reflect.Value, constructorInputCount)
+	getDependency := newDependenciesResolver(container, newMethodArgumentNames, isCached)
 
 	var errors []error
 	for i := 0; i < constructorInputCount; i++ {
-		reflectedNewMethodArgument := reflectedNewMethod.Type().In(i)
+		reflectedNewMethodArgument := reflectedNewMethod.Type().In(skippedInputCount + i)
 
 		dependencyName := newMethodArgumentNames[i]
 
-		dependencyFromContainer, err := container.GetSecure(dependencyName, isCached)
+		dependencyFromContainer, err := getDependency(i)
 		if err != nil {
 			errors = append(errors, err)
 			continue
 		}
 
-		reflectedDependencyFromContainer := reflect.ValueOf(dependencyFromContainer)
-		reflectedDependencyFromContainer = replaceCompatibleNilDependency(
+		argumentsToCallNewMethod[i], err = getValidFunctionArgument(
 			reflectedNewMethodArgument,
-			reflectedDependencyFromContainer,
 			dependencyFromContainer,
+			dependencyName,
+			serviceId,
+			isParameterDependency(container, dependencyName),
 		)
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
 
-		err = assertCompatible(
+	return argumentsToCallNewMethod, mergeErrors(errors)
+}
+
+//getValidFunctionArgument converts a dependency fetched from the container to the argument of a New method
+//or returns an error if it's not compatible, only string parameters are converted to arguments of other types
+func getValidFunctionArgument(
+	reflectedNewMethodArgument reflect.Type,
+	dependencyFromContainer interface{},
+	dependencyName,
+	serviceId string,
+	isParameter bool,
+) (reflect.Value, error) {
+	if _, isTagReference := parseTagReference(dependencyName); isTagReference {
+		return getTaggedServicesArgument(reflectedNewMethodArgument, dependencyFromContainer, dependencyName, serviceId)
+	}
+
+	//a missing optional dependency or a nil one is given as the zero value of any argument type
+	if _, isOptional := parseOptionalDependency(dependencyName); isOptional && dependencyFromContainer == nil {
+		return reflect.Zero(reflectedNewMethodArgument), nil
+	}
+
+	return getValidDependencyArgument(
+		reflectedNewMethodArgument,
+		dependencyFromContainer,
+		dependencyName,
+		serviceId,
+		isParameter,
+	)
+}
+
+func getValidDependencyArgument(
+	reflectedNewMethodArgument reflect.Type,
+	dependencyFromContainer interface{},
+	dependencyName,
+	serviceId string,
+	isParameter bool,
+) (reflect.Value, error) {
+	reflectedDependencyFromContainer := reflect.ValueOf(dependencyFromContainer)
+	reflectedDependencyFromContainer = replaceCompatibleNilDependency(
+		reflectedNewMethodArgument,
+		reflectedDependencyFromContainer,
+		dependencyFromContainer,
+	)
+
+	if isParameter {
+		var err error
+		reflectedDependencyFromContainer, err = convertStringDependency(
 			reflectedNewMethodArgument,
-			reflect.TypeOf(dependencyFromContainer),
+			reflectedDependencyFromContainer,
 			dependencyName,
 			serviceId,
 		)
 		if err != nil {
-			errors = append(errors, err)
-		} else {
-			argumentsToCallNewMethod[i] = reflectedDependencyFromContainer
+			return reflect.Value{}, err
 		}
 	}
 
-	return argumentsToCallNewMethod, mergeErrors(errors)
+	providedDependencyType := reflect.TypeOf(dependencyFromContainer)
+	if dependencyFromContainer != nil {
+		providedDependencyType = reflectedDependencyFromContainer.Type()
+	}
+
+	err := assertCompatible(
+		reflectedNewMethodArgument,
+		providedDependencyType,
+		dependencyName,
+		serviceId,
+	)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return reflectedDependencyFromContainer, nil
 }
 
 //replaceCompatibleNilDependency will return correct reflect value if dependency from container is nil and it is
@@ -143,3 +143,16 @@ func TestVariadicConstructor(t *testing.T) {
 		t.Errorf("Unexpected urls output %v for dynamic dependencies list", urls)
 	}
 }
+
+func TestAllFailedVariadicDependenciesAreReported(t *testing.T) {
+	cont := NewRuntimeContainer()
+	cont.AddNewMethod("urlProvider", mocks.NewUrlProviderWithDomain, "domain", "url1", "url2")
+
+	defer ExpectPanic(
+		t,
+		"Unknown dependency 'domain' [check 'urlProvider' service];\n"+
+			"Unknown dependency 'url1' [check 'urlProvider' service];\n"+
+			"Unknown dependency 'url2' [check 'urlProvider' service]",
+	)
+	cont.Get("urlProvider", true)
+}
@@ -0,0 +1,5 @@
+module github.com/breathbath/gotainer
+
+go 1.20
+
+require gopkg.in/yaml.v3 v3.0.1